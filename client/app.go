@@ -16,6 +16,10 @@ var lock = sync.Mutex{}
 var shutdownInProgress = false
 
 func CreateService(sdkId string, sdkSecret string, env string) (error, services.FlagsenseService) {
+	return CreateServiceWithOptions(sdkId, sdkSecret, env)
+}
+
+func CreateServiceWithOptions(sdkId string, sdkSecret string, env string, opts ...Option) (error, services.FlagsenseService) {
 	if strings.TrimSpace(sdkId) == "" || strings.TrimSpace(sdkSecret) == "" {
 		return errors.New("empty sdk params not allowed"), nil
 	}
@@ -29,55 +33,70 @@ func CreateService(sdkId string, sdkSecret string, env string) (error, services.
 	if !enums.NewEnvironment(env).IsValid(env) {
 		env = constants.PROD
 	}
-	flagsenseServiceMap[sdkId] = impl.NewFlagsenseService(sdkId, sdkSecret, enums.NewEnvironment(env))
+	options := impl.Options{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if err := options.Validate(); err != nil {
+		return err, nil
+	}
+	flagsenseServiceMap[sdkId] = impl.NewFlagsenseServiceWithOptions(sdkId, sdkSecret, enums.NewEnvironment(env), options)
 	fs, _ := flagsenseServiceMap[sdkId]
 	return nil, fs
 }
 
 func User(userId string, attributes map[string]interface{}) model.FSUser {
 	return model.FSUser{
-		userId,
-		attributes,
+		UserId:     userId,
+		Attributes: attributes,
 	}
 }
 
 func BooleanFlag(flagId string, defaultKey string, defaultValue bool) model.FSFlag {
 	return model.FSFlag{
-		flagId,
-		defaultKey,
-		defaultValue,
+		FlagId:       flagId,
+		DefaultKey:   defaultKey,
+		DefaultValue: defaultValue,
 	}
 }
 
 func IntegerFlag(flagId string, defaultKey string, defaultValue int32) model.FSFlag {
 	return model.FSFlag{
-		flagId,
-		defaultKey,
-		defaultValue,
+		FlagId:       flagId,
+		DefaultKey:   defaultKey,
+		DefaultValue: defaultValue,
+	}
+}
+
+func Int64Flag(flagId string, defaultKey string, defaultValue int64) model.FSFlag {
+	return model.FSFlag{
+		FlagId:       flagId,
+		DefaultKey:   defaultKey,
+		DefaultValue: defaultValue,
 	}
 }
 
 func DecimalFlag(flagId string, defaultKey string, defaultValue float64) model.FSFlag {
 	return model.FSFlag{
-		flagId,
-		defaultKey,
-		defaultValue,
+		FlagId:       flagId,
+		DefaultKey:   defaultKey,
+		DefaultValue: defaultValue,
 	}
 }
 
 func StringFlag(flagId string, defaultKey string, defaultValue string) model.FSFlag {
 	return model.FSFlag{
-		flagId,
-		defaultKey,
-		defaultValue,
+		FlagId:       flagId,
+		DefaultKey:   defaultKey,
+		DefaultValue: defaultValue,
 	}
 }
 
 func MapFlag(flagId string, defaultKey string, defaultValue map[string]interface{}) model.FSFlag {
 	return model.FSFlag{
-		flagId,
-		defaultKey,
-		defaultValue,
+		FlagId:       flagId,
+		DefaultKey:   defaultKey,
+		DefaultValue: defaultValue,
 	}
 }
 
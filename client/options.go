@@ -0,0 +1,124 @@
+package client
+
+import (
+	"github.com/flagsense/go-sdk/pkg/services"
+	"github.com/flagsense/go-sdk/pkg/services/impl"
+	"github.com/teltech/logger"
+	"net/http"
+	"time"
+)
+
+// Option overrides a part of the default sdk configuration read from assets/config/prod.json
+type Option func(options *impl.Options)
+
+func WithSDKServiceUrl(url string) Option {
+	return func(options *impl.Options) {
+		options.SDKServiceUrl = url
+	}
+}
+
+func WithEventsServiceUrl(url string) Option {
+	return func(options *impl.Options) {
+		options.EventsServiceUrl = url
+	}
+}
+
+// WithStreaming keeps a server-sent events connection open for flag updates, polling is only used while the stream is down
+func WithStreaming(streaming bool) Option {
+	return func(options *impl.Options) {
+		options.Streaming = streaming
+	}
+}
+
+func WithStreamingUrl(url string) Option {
+	return func(options *impl.Options) {
+		options.StreamingUrl = url
+	}
+}
+
+// WithDataFile runs the sdk offline on a json file shaped like the fetchLatest response, event capturing is disabled.
+// When watch is set the file is reloaded whenever it changes.
+func WithDataFile(filePath string, watch bool) Option {
+	return func(options *impl.Options) {
+		options.DataFile = filePath
+		options.WatchDataFile = watch
+	}
+}
+
+// WithCacheFile keeps the last fetched flags data in a local file. On start the cached data is served until the
+// first fetch, the data source status is marked stale meanwhile.
+func WithCacheFile(filePath string) Option {
+	return func(options *impl.Options) {
+		options.CacheFile = filePath
+	}
+}
+
+// WithFeatureStore replaces the in-memory store of flags data, e.g. with impl.NewFileFeatureStore to share it
+// between processes
+func WithFeatureStore(store services.FeatureStore) Option {
+	return func(options *impl.Options) {
+		options.FeatureStore = store
+	}
+}
+
+// WithDaemonMode stops fetching flags data, the sdk only reads the feature store populated by another process,
+// e.g. a relay. The store is refreshed every polling interval, 5 seconds by default. It needs a persistent store set
+// with WithFeatureStore, CreateServiceWithOptions returns impl.ErrDaemonModeWithoutStore otherwise.
+func WithDaemonMode(daemon bool) Option {
+	return func(options *impl.Options) {
+		options.DaemonMode = daemon
+	}
+}
+
+// WithDataSource replaces the flagsense servers with a custom source of flags data, e.g. fstestdata.TestData
+func WithDataSource(factory services.DataSourceFactory) Option {
+	return func(options *impl.Options) {
+		options.DataSourceFactory = factory
+	}
+}
+
+func WithPollingInterval(interval time.Duration) Option {
+	return func(options *impl.Options) {
+		options.PollingInterval = interval
+	}
+}
+
+// WithEventFlushInterval sets how often evaluation counts are sent, intervals below one second are raised to one second
+func WithEventFlushInterval(interval time.Duration) Option {
+	return func(options *impl.Options) {
+		options.EventFlushInterval = interval
+	}
+}
+
+func WithCaptureEvents(captureEvents bool) Option {
+	return func(options *impl.Options) {
+		options.CaptureEvents = &captureEvents
+	}
+}
+
+// WithClock replaces time.Now for rules relative to the current time, such as WITHIN_LAST_DAYS
+func WithClock(clock func() time.Time) Option {
+	return func(options *impl.Options) {
+		options.Clock = clock
+	}
+}
+
+// WithPrerelease sets how VERSION rules treat prerelease versions of users,
+// one of impl.PRERELEASE_STRICT (the default), impl.PRERELEASE_CORE and impl.PRERELEASE_EXCLUDE
+func WithPrerelease(mode string) Option {
+	return func(options *impl.Options) {
+		options.Prerelease = mode
+	}
+}
+
+func WithHttpClient(client *http.Client) Option {
+	return func(options *impl.Options) {
+		options.HttpClient = client
+	}
+}
+
+func WithLogger(log *logger.Log) Option {
+	return func(options *impl.Options) {
+		options.Logger = log
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/flagsense/go-sdk/pkg/relay"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	ENVIRONMENTS_VARIABLE = "FLAGSENSE_RELAY_ENVIRONMENTS"
+	SHUTDOWN_TIMEOUT      = 10 * time.Second
+)
+
+// environmentsFlag collects sdkId:sdkSecret:environment entries, the flag may be repeated
+type environmentsFlag []relay.EnvironmentConfig
+
+func (ef *environmentsFlag) String() string {
+	entries := []string{}
+	for _, environment := range *ef {
+		entries = append(entries, environment.SDKId+":"+environment.Environment)
+	}
+	return strings.Join(entries, ",")
+}
+
+func (ef *environmentsFlag) Set(value string) error {
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		// the secret may contain ':', the sdk id and the environment never do
+		first := strings.Index(entry, ":")
+		last := strings.LastIndex(entry, ":")
+		if first == last || first == 0 || last == len(entry)-1 {
+			return fmt.Errorf("expected sdkId:sdkSecret:environment, got %s", entry)
+		}
+		*ef = append(*ef, relay.EnvironmentConfig{
+			SDKId:       entry[:first],
+			SDKSecret:   entry[first+1 : last],
+			Environment: strings.ToUpper(entry[last+1:]),
+		})
+	}
+	return nil
+}
+
+func main() {
+	var environments environmentsFlag
+	flag.Var(&environments, "env", "sdkId:sdkSecret:environment to relay, may be repeated or comma separated (also "+ENVIRONMENTS_VARIABLE+")")
+	addr := flag.String("addr", ":8080", "address to listen on")
+	sdkServiceUrl := flag.String("sdk-service-url", "", "flagsense sdk service url")
+	eventsServiceUrl := flag.String("events-service-url", "", "flagsense events service url")
+	streaming := flag.Bool("streaming", false, "receive flag updates over the streaming connection")
+	pollingInterval := flag.Duration("polling-interval", 0, "polling interval, 5 minutes by default")
+	storeDir := flag.String("store-dir", "", "directory for file feature stores shared with daemon mode sdk instances")
+	flag.Parse()
+
+	if value := os.Getenv(ENVIRONMENTS_VARIABLE); value != "" {
+		if err := environments.Set(value); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *storeDir != "" {
+		for i := range environments {
+			environments[i].StoreFile = filepath.Join(*storeDir, environments[i].SDKId+"-"+strings.ToLower(environments[i].Environment)+".json")
+		}
+	}
+
+	flagsRelay, err := relay.NewRelay(relay.Config{
+		Environments:     environments,
+		SDKServiceUrl:    *sdkServiceUrl,
+		EventsServiceUrl: *eventsServiceUrl,
+		Streaming:        *streaming,
+		PollingInterval:  *pollingInterval,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	server := &http.Server{
+		Addr:    *addr,
+		Handler: flagsRelay.Handler(),
+	}
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		<-signals
+		ctx, cancel := context.WithTimeout(context.Background(), SHUTDOWN_TIMEOUT)
+		defer cancel()
+		server.Shutdown(ctx)
+	}()
+
+	log.Printf("flagsense relay listening on %s", *addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+	flagsRelay.Close()
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/flagsense/go-sdk/pkg/relay"
+)
+
+func TestEnvironmentsFlag(t *testing.T) {
+	var environments environmentsFlag
+	if err := environments.Set("sdk:secret:dev, sdk:se:cr:et:prod"); err != nil {
+		t.Fatal(err)
+	}
+	expected := []relay.EnvironmentConfig{
+		{SDKId: "sdk", SDKSecret: "secret", Environment: "DEV"},
+		{SDKId: "sdk", SDKSecret: "se:cr:et", Environment: "PROD"},
+	}
+	if len(environments) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, environments)
+	}
+	for i := range expected {
+		if environments[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected[i], environments[i])
+		}
+	}
+
+	for _, value := range []string{"sdk:secret", ":secret:dev", "sdk:secret:", "sdk"} {
+		if err := environments.Set(value); err == nil {
+			t.Fatalf("expected an error for %s", value)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"github.com/flagsense/go-sdk/constants"
+	"github.com/flagsense/go-sdk/pkg/enums"
+	"github.com/flagsense/go-sdk/pkg/services/impl"
+	"github.com/flagsense/go-sdk/pkg/sidecar"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	SDK_ID_VARIABLE      = "FLAGSENSE_SDK_ID"
+	SDK_SECRET_VARIABLE  = "FLAGSENSE_SDK_SECRET"
+	ENVIRONMENT_VARIABLE = "FLAGSENSE_ENVIRONMENT"
+	SHUTDOWN_TIMEOUT     = 10 * time.Second
+)
+
+func main() {
+	addr := flag.String("addr", ":8081", "address to listen on")
+	sdkId := flag.String("sdk-id", os.Getenv(SDK_ID_VARIABLE), "flagsense sdk id (also "+SDK_ID_VARIABLE+")")
+	sdkSecret := flag.String("sdk-secret", os.Getenv(SDK_SECRET_VARIABLE), "flagsense sdk secret (also "+SDK_SECRET_VARIABLE+")")
+	environment := flag.String("env", os.Getenv(ENVIRONMENT_VARIABLE), "flagsense environment (also "+ENVIRONMENT_VARIABLE+")")
+	sdkServiceUrl := flag.String("sdk-service-url", "", "flagsense sdk service url, e.g. a relay")
+	eventsServiceUrl := flag.String("events-service-url", "", "flagsense events service url, e.g. a relay")
+	streaming := flag.Bool("streaming", false, "receive flag updates over the streaming connection")
+	pollingInterval := flag.Duration("polling-interval", 0, "polling interval, 5 minutes by default")
+	storeFile := flag.String("store-file", "", "file feature store, shared with other sdk instances")
+	daemon := flag.Bool("daemon", false, "only read the file feature store written by a relay")
+	flag.Parse()
+
+	if strings.TrimSpace(*sdkId) == "" || strings.TrimSpace(*sdkSecret) == "" {
+		log.Fatal("empty sdk params not allowed")
+	}
+	env := strings.ToUpper(*environment)
+	if !enums.NewEnvironment(env).IsValid(env) {
+		env = constants.PROD
+	}
+	options := impl.Options{
+		SDKServiceUrl:    *sdkServiceUrl,
+		EventsServiceUrl: *eventsServiceUrl,
+		Streaming:        *streaming,
+		PollingInterval:  *pollingInterval,
+		DaemonMode:       *daemon,
+	}
+	if *storeFile != "" {
+		options.FeatureStore = impl.NewFileFeatureStore(*storeFile)
+	}
+	if err := options.Validate(); err != nil {
+		log.Fatalf("%v, set -store-file", err)
+	}
+	service := impl.NewFlagsenseServiceWithOptions(*sdkId, *sdkSecret, enums.NewEnvironment(env), options)
+
+	server := &http.Server{
+		Addr:    *addr,
+		Handler: sidecar.NewSidecar(service).Handler(),
+	}
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		<-signals
+		ctx, cancel := context.WithTimeout(context.Background(), SHUTDOWN_TIMEOUT)
+		defer cancel()
+		server.Shutdown(ctx)
+	}()
+
+	log.Printf("flagsense sidecar listening on %s", *addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+	service.Close()
+}
@@ -0,0 +1,132 @@
+// Package flagsense has typed handles on flags, so call sites get their values without type assertions
+package flagsense
+
+import (
+	"context"
+	"fmt"
+	"github.com/flagsense/go-sdk/pkg/model"
+	"github.com/flagsense/go-sdk/pkg/services"
+)
+
+// Detail is the evaluation detail of a typed flag, its Value holds the value returned by Get
+type Detail = model.FSEvaluationDetail
+
+// Flag is a typed handle on a flag, the default value is returned whenever the evaluation fails
+type Flag[T any] struct {
+	Id       string
+	Default  T
+	evaluate func(ctx context.Context, service services.FlagsenseService, flagId string, user model.FSUser) Detail
+	convert  func(value interface{}) (T, error)
+}
+
+func Bool(flagId string, defaultValue bool) Flag[bool] {
+	return Flag[bool]{
+		Id:      flagId,
+		Default: defaultValue,
+		evaluate: func(ctx context.Context, service services.FlagsenseService, flagId string, user model.FSUser) Detail {
+			return service.BooleanVariationDetailCtx(ctx, model.FSFlag{FlagId: flagId, DefaultValue: false}, user)
+		},
+		convert: func(value interface{}) (bool, error) {
+			converted, ok := value.(bool)
+			if !ok {
+				return false, fmt.Errorf("Value is not boolean, %+v", value)
+			}
+			return converted, nil
+		},
+	}
+}
+
+func String(flagId string, defaultValue string) Flag[string] {
+	return Flag[string]{
+		Id:      flagId,
+		Default: defaultValue,
+		evaluate: func(ctx context.Context, service services.FlagsenseService, flagId string, user model.FSUser) Detail {
+			return service.StringVariationDetailCtx(ctx, model.FSFlag{FlagId: flagId, DefaultValue: ""}, user)
+		},
+		convert: func(value interface{}) (string, error) {
+			converted, ok := value.(string)
+			if !ok {
+				return "", fmt.Errorf("Value is not string, %+v", value)
+			}
+			return converted, nil
+		},
+	}
+}
+
+func Int64(flagId string, defaultValue int64) Flag[int64] {
+	return Flag[int64]{
+		Id:      flagId,
+		Default: defaultValue,
+		evaluate: func(ctx context.Context, service services.FlagsenseService, flagId string, user model.FSUser) Detail {
+			return service.Int64VariationDetailCtx(ctx, model.FSFlag{FlagId: flagId, DefaultValue: int64(0)}, user)
+		},
+		convert: func(value interface{}) (int64, error) {
+			converted, ok := value.(int64)
+			if !ok {
+				return 0, fmt.Errorf("Value is not int64, %+v", value)
+			}
+			return converted, nil
+		},
+	}
+}
+
+func Float64(flagId string, defaultValue float64) Flag[float64] {
+	return Flag[float64]{
+		Id:      flagId,
+		Default: defaultValue,
+		evaluate: func(ctx context.Context, service services.FlagsenseService, flagId string, user model.FSUser) Detail {
+			return service.DecimalVariationDetailCtx(ctx, model.FSFlag{FlagId: flagId, DefaultValue: float64(0)}, user)
+		},
+		convert: func(value interface{}) (float64, error) {
+			converted, ok := value.(float64)
+			if !ok {
+				return 0, fmt.Errorf("Value is not float64, %+v", value)
+			}
+			return converted, nil
+		},
+	}
+}
+
+// JSON decodes the variant of a JSON flag into T, see FlagsenseService.JSONVariation
+func JSON[T any](flagId string, defaultValue T) Flag[T] {
+	return Flag[T]{
+		Id:      flagId,
+		Default: defaultValue,
+		evaluate: func(ctx context.Context, service services.FlagsenseService, flagId string, user model.FSUser) Detail {
+			var decoded T
+			detail := service.JSONVariationCtx(ctx, model.FSFlag{FlagId: flagId}, user, &decoded)
+			detail.Value = decoded
+			return detail
+		},
+		convert: func(value interface{}) (T, error) {
+			converted, ok := value.(T)
+			if !ok {
+				return converted, fmt.Errorf("Value is not %T, %+v", converted, value)
+			}
+			return converted, nil
+		},
+	}
+}
+
+func (f Flag[T]) Get(ctx context.Context, service services.FlagsenseService, user model.FSUser) (T, Detail) {
+	detail := f.evaluate(ctx, service, f.Id, user)
+	if detail.Reason.IsError() {
+		detail.Value = f.Default
+		return f.Default, detail
+	}
+	value, err := f.convert(detail.Value)
+	if err != nil {
+		return f.Default, Detail{
+			Value:  f.Default,
+			Reason: model.NewErrorReason(model.ERROR_WRONG_TYPE),
+		}
+	}
+	detail.Value = value
+	return value, detail
+}
+
+// Value is Get without the detail
+func (f Flag[T]) Value(ctx context.Context, service services.FlagsenseService, user model.FSUser) T {
+	value, _ := f.Get(ctx, service, user)
+	return value
+}
@@ -1,12 +1,25 @@
 module github.com/flagsense/go-sdk
 
-go 1.14
+go 1.18
 
 require (
 	github.com/gobuffalo/packr/v2 v2.8.1
-	github.com/google/uuid v1.3.0 // indirect
+	github.com/google/uuid v1.3.0
 	github.com/hashicorp/go-version v1.3.0
 	github.com/orcaman/concurrent-map v0.0.0-20210501183033-44dafcb38ecc // indirect 44dafcb38eccb499e306882f3b6e0ae4e0a74878
 	github.com/teltech/logger v1.2.2
 	github.com/twmb/murmur3 v1.0.0
 )
+
+require (
+	github.com/gobuffalo/logger v1.0.3 // indirect
+	github.com/gobuffalo/packd v1.0.0 // indirect
+	github.com/karrick/godirwalk v1.15.8 // indirect
+	github.com/konsorten/go-windows-terminal-sequences v1.0.2 // indirect
+	github.com/markbates/errx v1.1.0 // indirect
+	github.com/markbates/oncer v1.0.0 // indirect
+	github.com/markbates/safe v1.0.1 // indirect
+	github.com/sirupsen/logrus v1.4.2 // indirect
+	golang.org/x/crypto v0.0.0-20191122220453-ac88ee75c92c // indirect
+	golang.org/x/sys v0.0.0-20190422165155-953cdadca894 // indirect
+)
@@ -0,0 +1,14 @@
+module github.com/flagsense/go-sdk/openfeature
+
+go 1.18
+
+require (
+	github.com/flagsense/go-sdk v0.0.0-20261018093018-95605b0de1c3
+	github.com/open-feature/go-sdk v1.9.0
+)
+
+require (
+	github.com/go-logr/logr v1.3.0 // indirect
+	github.com/hashicorp/go-version v1.3.0 // indirect
+	golang.org/x/exp v0.0.0-20230811145659-89c5cff77bcb // indirect
+)
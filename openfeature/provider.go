@@ -0,0 +1,281 @@
+package openfeature
+
+import (
+	"context"
+	"github.com/flagsense/go-sdk/pkg/model"
+	"github.com/flagsense/go-sdk/pkg/services"
+	of "github.com/open-feature/go-sdk/openfeature"
+	"sync"
+)
+
+const (
+	PROVIDER_NAME       = "flagsense"
+	EVENTS_BUFFER_SIZE  = 100
+	SEGMENT_ID_METADATA = "segmentId"
+	BUCKET_BY_METADATA  = "bucketBy"
+)
+
+// Provider is an OpenFeature provider on top of a flagsense service. The targeting key of the evaluation context
+// is the user id, all other context attributes become user attributes. The service stays owned by the caller,
+// Shutdown only stops the provider events.
+type Provider struct {
+	Service services.FlagsenseService
+	events  chan of.Event
+	lock    *sync.Mutex
+	cancel  context.CancelFunc
+}
+
+func NewProvider(service services.FlagsenseService) *Provider {
+	return &Provider{
+		Service: service,
+		events:  make(chan of.Event, EVENTS_BUFFER_SIZE),
+		lock:    &sync.Mutex{},
+	}
+}
+
+func (p *Provider) Metadata() of.Metadata {
+	return of.Metadata{Name: PROVIDER_NAME}
+}
+
+func (p *Provider) Hooks() []of.Hook {
+	return []of.Hook{}
+}
+
+// Status follows the data source, stale data is served from the cache file or while updates fail
+func (p *Provider) Status() of.State {
+	status := p.Service.DataSourceStatus()
+	switch {
+	case status.State == model.DATA_SOURCE_FAILED:
+		return of.ErrorState
+	case status.State == model.DATA_SOURCE_INTERRUPTED || status.Stale:
+		return of.StaleState
+	case status.State == model.DATA_SOURCE_VALID:
+		return of.ReadyState
+	default:
+		return of.NotReadyState
+	}
+}
+
+func (p *Provider) Init(evaluationContext of.EvaluationContext) error {
+	return p.InitWithContext(context.Background(), evaluationContext)
+}
+
+// InitWithContext waits for the flags data, a failed data source, e.g. on bad credentials, fails the provider
+func (p *Provider) InitWithContext(ctx context.Context, evaluationContext of.EvaluationContext) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.cancel != nil {
+		return nil
+	}
+	if err := p.Service.WaitForInitialization(ctx); err != nil {
+		return err
+	}
+	// listeners are added before returning, so no change after the initialization is missed
+	watchCtx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	go p.watchStatus(watchCtx, p.Service.GetDataSourceStatusProvider().AddStatusListener())
+	go p.watchFlags(watchCtx, p.Service.AddFlagChangeListener())
+	return nil
+}
+
+func (p *Provider) Shutdown() {
+	p.ShutdownWithContext(context.Background())
+}
+
+func (p *Provider) ShutdownWithContext(ctx context.Context) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.cancel != nil {
+		p.cancel()
+		p.cancel = nil
+	}
+	return nil
+}
+
+func (p *Provider) EventChannel() <-chan of.Event {
+	return p.events
+}
+
+func (p *Provider) BooleanEvaluation(ctx context.Context, flag string, defaultValue bool, flatCtx of.FlattenedContext) of.BoolResolutionDetail {
+	detail := p.Service.BooleanVariationDetailCtx(ctx, model.FSFlag{FlagId: flag, DefaultValue: defaultValue}, toUser(flatCtx))
+	value, ok := detail.Value.(bool)
+	if !ok || detail.Reason.IsError() {
+		value = defaultValue
+	}
+	return of.BoolResolutionDetail{
+		Value:                    value,
+		ProviderResolutionDetail: toResolutionDetail(detail),
+	}
+}
+
+func (p *Provider) StringEvaluation(ctx context.Context, flag string, defaultValue string, flatCtx of.FlattenedContext) of.StringResolutionDetail {
+	detail := p.Service.StringVariationDetailCtx(ctx, model.FSFlag{FlagId: flag, DefaultValue: defaultValue}, toUser(flatCtx))
+	value, ok := detail.Value.(string)
+	if !ok || detail.Reason.IsError() {
+		value = defaultValue
+	}
+	return of.StringResolutionDetail{
+		Value:                    value,
+		ProviderResolutionDetail: toResolutionDetail(detail),
+	}
+}
+
+func (p *Provider) FloatEvaluation(ctx context.Context, flag string, defaultValue float64, flatCtx of.FlattenedContext) of.FloatResolutionDetail {
+	detail := p.Service.DecimalVariationDetailCtx(ctx, model.FSFlag{FlagId: flag, DefaultValue: defaultValue}, toUser(flatCtx))
+	value, ok := detail.Value.(float64)
+	if !ok || detail.Reason.IsError() {
+		value = defaultValue
+	}
+	return of.FloatResolutionDetail{
+		Value:                    value,
+		ProviderResolutionDetail: toResolutionDetail(detail),
+	}
+}
+
+func (p *Provider) IntEvaluation(ctx context.Context, flag string, defaultValue int64, flatCtx of.FlattenedContext) of.IntResolutionDetail {
+	detail := p.Service.Int64VariationDetailCtx(ctx, model.FSFlag{FlagId: flag, DefaultValue: defaultValue}, toUser(flatCtx))
+	value, ok := detail.Value.(int64)
+	if !ok || detail.Reason.IsError() {
+		return of.IntResolutionDetail{
+			Value:                    defaultValue,
+			ProviderResolutionDetail: toResolutionDetail(detail),
+		}
+	}
+	return of.IntResolutionDetail{
+		Value:                    value,
+		ProviderResolutionDetail: toResolutionDetail(detail),
+	}
+}
+
+func (p *Provider) ObjectEvaluation(ctx context.Context, flag string, defaultValue interface{}, flatCtx of.FlattenedContext) of.InterfaceResolutionDetail {
+	var value interface{}
+	detail := p.Service.JSONVariationCtx(ctx, model.FSFlag{FlagId: flag}, toUser(flatCtx), &value)
+	if detail.Reason.IsError() {
+		value = defaultValue
+	}
+	return of.InterfaceResolutionDetail{
+		Value:                    value,
+		ProviderResolutionDetail: toResolutionDetail(detail),
+	}
+}
+
+// watchStatus turns data source status changes into provider events, stale data is served from the cache file or
+// while updates fail
+func (p *Provider) watchStatus(ctx context.Context, listener <-chan model.FSDataSourceStatus) {
+	statusProvider := p.Service.GetDataSourceStatusProvider()
+	defer statusProvider.RemoveStatusListener(listener)
+
+	stale := false
+	status := statusProvider.GetStatus()
+	for {
+		switch {
+		case status.State == model.DATA_SOURCE_FAILED:
+			p.emit(of.ProviderError, of.ProviderEventDetails{Message: status.LastError})
+		case status.State == model.DATA_SOURCE_INTERRUPTED || status.Stale:
+			if !stale {
+				stale = true
+				p.emit(of.ProviderStale, of.ProviderEventDetails{Message: status.LastError})
+			}
+		case status.State == model.DATA_SOURCE_VALID:
+			if stale {
+				stale = false
+				p.emit(of.ProviderReady, of.ProviderEventDetails{})
+			}
+		}
+
+		select {
+		case status = <-listener:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (p *Provider) watchFlags(ctx context.Context, listener <-chan model.FSFlagChangeEvent) {
+	defer p.Service.RemoveFlagChangeListener(listener)
+	for {
+		select {
+		case event := <-listener:
+			p.emit(of.ProviderConfigChange, of.ProviderEventDetails{FlagChanges: []string{event.FlagId}})
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// emit drops events nobody reads, the OpenFeature sdk always drains the channel
+func (p *Provider) emit(eventType of.EventType, details of.ProviderEventDetails) {
+	select {
+	case p.events <- of.Event{ProviderName: PROVIDER_NAME, EventType: eventType, ProviderEventDetails: details}:
+	default:
+	}
+}
+
+func toUser(flatCtx of.FlattenedContext) model.FSUser {
+	user := model.FSUser{
+		Attributes: map[string]interface{}{},
+	}
+	for key, value := range flatCtx {
+		if key == of.TargetingKey {
+			user.UserId, _ = value.(string)
+			continue
+		}
+		user.Attributes[key] = value
+	}
+	return user
+}
+
+func toResolutionDetail(detail model.FSEvaluationDetail) of.ProviderResolutionDetail {
+	resolutionDetail := of.ProviderResolutionDetail{
+		Reason:  toReason(detail.Reason),
+		Variant: detail.Key,
+	}
+	if detail.Reason.SegmentId != "" || detail.Reason.BucketBy != "" {
+		resolutionDetail.FlagMetadata = of.FlagMetadata{}
+	}
+	if detail.Reason.SegmentId != "" {
+		resolutionDetail.FlagMetadata[SEGMENT_ID_METADATA] = detail.Reason.SegmentId
+	}
+	if detail.Reason.BucketBy != "" {
+		resolutionDetail.FlagMetadata[BUCKET_BY_METADATA] = detail.Reason.BucketBy
+	}
+	if detail.Reason.IsError() {
+		resolutionDetail.Variant = ""
+		resolutionDetail.ResolutionError = toResolutionError(detail.Reason.ErrorKind)
+	}
+	return resolutionDetail
+}
+
+func toReason(reason model.FSEvaluationReason) of.Reason {
+	switch reason.Kind {
+	case model.REASON_OFF:
+		return of.DisabledReason
+	case model.REASON_TARGET_MATCH, model.REASON_SEGMENT_MATCH:
+		return of.TargetingMatchReason
+	case model.REASON_FALLTHROUGH:
+		return of.SplitReason
+	case model.REASON_PREREQUISITE_FAILED:
+		return of.DefaultReason
+	case model.REASON_ERROR:
+		return of.ErrorReason
+	default:
+		return of.UnknownReason
+	}
+}
+
+func toResolutionError(errorKind string) of.ResolutionError {
+	switch errorKind {
+	case model.ERROR_CLIENT_NOT_READY:
+		return of.NewProviderNotReadyResolutionError("flags data is still loading")
+	case model.ERROR_FLAG_NOT_FOUND:
+		return of.NewFlagNotFoundResolutionError("flag not found")
+	case model.ERROR_WRONG_TYPE:
+		return of.NewTypeMismatchResolutionError("flag has another type")
+	case model.ERROR_DECODE_FAILED:
+		return of.NewParseErrorResolutionError("flag value can not be decoded")
+	case model.ERROR_USER_NOT_SPECIFIED:
+		return of.NewTargetingKeyMissingResolutionError("targeting key is required")
+	default:
+		return of.NewGeneralResolutionError(errorKind)
+	}
+}
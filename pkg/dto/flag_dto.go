@@ -1,5 +1,7 @@
 package dto
 
+import "encoding/json"
+
 var VariantType = []string{
 	"INT",
 	"BOOL",
@@ -17,18 +19,19 @@ const (
 	ACTIVE   = "ACTIVE"
 	INACTIVE = "INACTIVE"
 
-	INT32   = "INT32"
-	INT     = "INT"
-	FLOAT   = "FLOAT"
-	FLOAT32 = "FLOAT32"
-	INT64   = "INT64"
-	BOOL    = "BOOL"
-	FLOAT64 = "FLOAT64"
-	STRING  = "STRING"
-	JSON    = "JSON"
-	MAP     = "MAP"
-	DOUBLE  = "DOUBLE"
-	VERSION = "VERSION"
+	INT32    = "INT32"
+	INT      = "INT"
+	FLOAT    = "FLOAT"
+	FLOAT32  = "FLOAT32"
+	INT64    = "INT64"
+	BOOL     = "BOOL"
+	FLOAT64  = "FLOAT64"
+	STRING   = "STRING"
+	JSON     = "JSON"
+	MAP      = "MAP"
+	DOUBLE   = "DOUBLE"
+	VERSION  = "VERSION"
+	DATETIME = "DATETIME"
 
 	LT  = "LT"
 	LTE = "LTE"
@@ -40,6 +43,30 @@ const (
 	HAS = "HAS"
 	SW  = "SW"
 	EW  = "EW"
+
+	NIOF    = "NIOF"
+	MATCHES = "MATCHES"
+
+	// case-insensitive variants of the string operators
+	EQ_CI      = "EQ_CI"
+	HAS_CI     = "HAS_CI"
+	SW_CI      = "SW_CI"
+	EW_CI      = "EW_CI"
+	IOF_CI     = "IOF_CI"
+	NIOF_CI    = "NIOF_CI"
+	MATCHES_CI = "MATCHES_CI"
+
+	BEFORE           = "BEFORE"
+	AFTER            = "AFTER"
+	BETWEEN          = "BETWEEN"
+	WITHIN_LAST_DAYS = "WITHIN_LAST_DAYS"
+
+	// list operators, the attribute holds any or all of the rule values
+	CONTAINS_ANY = "CONTAINS_ANY"
+	CONTAINS_ALL = "CONTAINS_ALL"
+
+	// RANGE matches versions against go-version constraints, such as "~> 2.3" or ">= 1.2, < 2.0"
+	RANGE = "RANGE"
 )
 
 type FlagDTO struct {
@@ -48,7 +75,10 @@ type FlagDTO struct {
 	Variants      map[string]Variant `json:"variants"`
 	VariantsOrder []string           `json:"variantsOrder"`
 	Type          string             `json:"type"`
-	EnvData       EnvData            `json:"envData"`
+	ClientSide    bool               `json:"clientSide"`
+	// BucketBy is the user attribute hashed for percentage rollouts, the userId when it is empty or the user lacks it
+	BucketBy string  `json:"bucketBy,omitempty"`
+	EnvData  EnvData `json:"envData"`
 }
 
 type Variant struct {
@@ -56,6 +86,32 @@ type Variant struct {
 	Name  string      `json:"name"`
 }
 
+// UnmarshalJSON keeps a number value as json.Number, so an int64 variant is not rounded through float64.
+// Numbers nested in json values stay float64.
+func (v *Variant) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Value json.RawMessage `json:"value"`
+		Name  string          `json:"name"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	v.Name = raw.Name
+	v.Value = nil
+	if len(raw.Value) == 0 {
+		return nil
+	}
+	if first := raw.Value[0]; first == '-' || (first >= '0' && first <= '9') {
+		var number json.Number
+		if err := json.Unmarshal(raw.Value, &number); err != nil {
+			return err
+		}
+		v.Value = number
+		return nil
+	}
+	return json.Unmarshal(raw.Value, &v.Value)
+}
+
 type EnvData struct {
 	PreRequisites       []string                  `json:"preRequisites"`
 	OffVariant          string                    `json:"offVariant"`
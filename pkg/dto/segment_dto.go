@@ -1,5 +1,14 @@
 package dto
 
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/flagsense/go-sdk/pkg/util"
+	"github.com/hashicorp/go-version"
+	"regexp"
+)
+
 type SegmentDTO struct {
 	ID    string        `json:"id"`
 	Rules [][]*RulesDTO `json:"rules"`
@@ -11,6 +20,15 @@ type RulesDTO struct {
 	Operator string        `json:"operator"`
 	Type     string        `json:"type"`
 	Values   []interface{} `json:"values"`
+	// MatchAll makes a list attribute match only when every element matches, instead of any element
+	MatchAll bool `json:"matchAll,omitempty"`
+	// Patterns are the compiled values of a MATCHES rule
+	Patterns []*regexp.Regexp `json:"-"`
+	// Versions and Constraints are the parsed values of a VERSION rule, Constraints only for RANGE
+	Versions    []*version.Version    `json:"-"`
+	Constraints []version.Constraints `json:"-"`
+	// Err is set when the rule can not be evaluated, such as for an invalid pattern
+	Err error `json:"-"`
 }
 
 func (sd *SegmentDTO) MatchType() {
@@ -21,40 +39,143 @@ func (sd *SegmentDTO) MatchType() {
 	}
 }
 
+// UnmarshalJSON keeps numbers as json.Number, so int64 values beyond float64 precision survive until MatchType
+func (rd *RulesDTO) UnmarshalJSON(data []byte) error {
+	type rulesDTO RulesDTO
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode((*rulesDTO)(rd))
+}
+
+// MatchType converts the values to the rule type, a value that does not convert sets Err and the values are kept as they are
 func (rd *RulesDTO) MatchType() {
-	if rd.Type == INT || rd.Type == INT32 || rd.Type == INT64 {
-		values := make([]interface{}, len(rd.Values))
-		for i, val := range rd.Values {
-			switch val.(type) {
-			case float64:
-				values[i] = int32(val.(float64))
-			case float32:
-				values[i] = int32(val.(float32))
-			case int32:
-				values[i] = int32(val.(int32))
-			case int64:
-				values[i] = int32(val.(int64))
-			default:
-				values[i] = int32(val.(float64))
+	rd.Patterns = nil
+	rd.Versions = nil
+	rd.Constraints = nil
+	rd.Err = nil
+	switch rd.Type {
+	case INT, INT32:
+		rd.convertValues(func(val interface{}) (interface{}, error) {
+			return util.ToInt32(val)
+		})
+	case INT64:
+		rd.convertValues(func(val interface{}) (interface{}, error) {
+			return util.ToInt64(val)
+		})
+	case FLOAT64, FLOAT32, FLOAT, DOUBLE:
+		rd.convertValues(func(val interface{}) (interface{}, error) {
+			if !util.IsNumber(val) {
+				return nil, fmt.Errorf("value is not a number, %+v", val)
 			}
+			return util.ConvertToFloat64(val), nil
+		})
+	case STRING:
+		rd.compileStrings()
+	case DATETIME:
+		rd.convertTimes()
+	case VERSION:
+		rd.parseVersions()
+	}
+}
+
+// parseVersions parses the versions, or the constraints of a RANGE rule, so invalid values fail the rule instead of being ignored
+func (rd *RulesDTO) parseVersions() {
+	if len(rd.Values) == 0 {
+		rd.Err = fmt.Errorf("rule %s has no version", rd.Key)
+		return
+	}
+	for _, val := range rd.Values {
+		value, ok := val.(string)
+		if !ok {
+			rd.Err = fmt.Errorf("rule %s has a version that is not a string, %+v", rd.Key, val)
+			return
 		}
-		rd.Values = values
-	} else if rd.Type == FLOAT64 || rd.Type == FLOAT32 || rd.Type == FLOAT {
-		values := make([]interface{}, len(rd.Values))
-		for i, val := range rd.Values {
-			switch val.(type) {
-			case float32:
-				values[i] = float64(val.(float32))
-			case int32:
-				values[i] = float64(val.(int32))
-			case int64:
-				values[i] = float64(val.(int64))
-			case int:
-				values[i] = float64(val.(int))
-			default:
-				values[i] = val.(float64)
+		if rd.Operator == RANGE {
+			constraints, err := version.NewConstraint(value)
+			if err != nil {
+				rd.Err = fmt.Errorf("rule %s has an invalid version range %q: %v", rd.Key, value, err)
+				return
+			}
+			rd.Constraints = append(rd.Constraints, constraints)
+			continue
+		}
+		parsed, err := version.NewVersion(value)
+		if err != nil {
+			rd.Err = fmt.Errorf("rule %s has an invalid version %q: %v", rd.Key, value, err)
+			return
+		}
+		rd.Versions = append(rd.Versions, parsed)
+	}
+}
+
+// convertTimes parses the values of a DATETIME rule, the number of days of WITHIN_LAST_DAYS is kept as float64
+func (rd *RulesDTO) convertTimes() {
+	required := 1
+	if rd.Operator == BETWEEN {
+		required = 2
+	}
+	if len(rd.Values) < required {
+		rd.Err = fmt.Errorf("rule %s needs %d values for %s", rd.Key, required, rd.Operator)
+		return
+	}
+
+	values := make([]interface{}, len(rd.Values))
+	for i, val := range rd.Values {
+		if rd.Operator == WITHIN_LAST_DAYS {
+			if !util.IsNumber(val) {
+				rd.Err = fmt.Errorf("rule %s has a number of days that is not a number, %+v", rd.Key, val)
+				return
 			}
+			values[i] = util.ConvertToFloat64(val)
+			continue
+		}
+		converted, err := util.ToTime(val)
+		if err != nil {
+			rd.Err = fmt.Errorf("rule %s has an invalid time %+v: %v", rd.Key, val, err)
+			return
+		}
+		values[i] = converted
+	}
+	rd.Values = values
+}
+
+// compileStrings checks that the values are strings and compiles the patterns of MATCHES rules once, instead of per evaluation
+func (rd *RulesDTO) compileStrings() {
+	for _, val := range rd.Values {
+		if _, ok := val.(string); !ok {
+			rd.Err = fmt.Errorf("rule %s has a value that is not a string, %+v", rd.Key, val)
+			return
+		}
+	}
+	if rd.Operator != MATCHES && rd.Operator != MATCHES_CI {
+		return
+	}
+
+	patterns := make([]*regexp.Regexp, len(rd.Values))
+	for i, val := range rd.Values {
+		pattern := val.(string)
+		if rd.Operator == MATCHES_CI {
+			pattern = "(?i)" + pattern
+		}
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			rd.Err = fmt.Errorf("rule %s has an invalid pattern %q: %v", rd.Key, val, err)
+			return
+		}
+		patterns[i] = compiled
+	}
+	rd.Patterns = patterns
+}
+
+func (rd *RulesDTO) convertValues(convert func(interface{}) (interface{}, error)) {
+	values := make([]interface{}, len(rd.Values))
+	for i, val := range rd.Values {
+		converted, err := convert(val)
+		if err != nil {
+			rd.Err = fmt.Errorf("rule %s has a value that is not %s, %+v: %v", rd.Key, rd.Type, val, err)
+			return
 		}
-		rd.Values = values
+		values[i] = converted
 	}
+	rd.Values = values
 }
@@ -1,5 +1,7 @@
 package dto
 
+import "github.com/flagsense/go-sdk/pkg/model"
+
 type UserVariantDTO struct {
 	UserId              string
 	Attributes          map[string]interface{}
@@ -9,4 +11,5 @@ type UserVariantDTO struct {
 	Key                 string
 	Value               interface{}
 	ExpectedVariantType string
+	Reason              model.FSEvaluationReason
 }
@@ -0,0 +1,230 @@
+package fstestdata
+
+import (
+	"fmt"
+	"github.com/flagsense/go-sdk/pkg/dto"
+	"reflect"
+)
+
+const (
+	TOTAL_TRAFFIC = 100000
+)
+
+type FlagBuilder struct {
+	id                  string
+	variantType         string
+	clientSide          bool
+	bucketBy            string
+	variants            map[string]interface{}
+	variantsOrder       []string
+	on                  bool
+	offVariant          string
+	fallthroughTraffic  map[string]int
+	targetUsers         map[string]string
+	targetSegments      map[string]map[string]int
+	targetSegmentsOrder []string
+	preRequisites       []string
+}
+
+func newFlagBuilder(flagId string) *FlagBuilder {
+	fb := &FlagBuilder{
+		id: flagId,
+	}
+	return fb.BooleanFlag()
+}
+
+// BooleanFlag resets the flag to a boolean flag which is on, serves true and is false when turned off
+func (fb *FlagBuilder) BooleanFlag() *FlagBuilder {
+	fb.reset(dto.BOOL)
+	return fb.Variations(true, false).On(true).FallthroughVariation(true).OffVariation(false)
+}
+
+func (fb *FlagBuilder) StringFlag() *FlagBuilder {
+	fb.reset(dto.STRING)
+	return fb
+}
+
+func (fb *FlagBuilder) IntegerFlag() *FlagBuilder {
+	fb.reset(dto.INT)
+	return fb
+}
+
+func (fb *FlagBuilder) DecimalFlag() *FlagBuilder {
+	fb.reset(dto.DOUBLE)
+	return fb
+}
+
+func (fb *FlagBuilder) MapFlag() *FlagBuilder {
+	fb.reset(dto.JSON)
+	return fb
+}
+
+// Variations declares the variants of the flag in order. Values used later on are added automatically,
+// the variant key of a value is its string form.
+func (fb *FlagBuilder) Variations(values ...interface{}) *FlagBuilder {
+	for _, value := range values {
+		fb.keyFor(value)
+	}
+	return fb
+}
+
+// Variant declares a variant with an explicit key
+func (fb *FlagBuilder) Variant(key string, value interface{}) *FlagBuilder {
+	if _, ok := fb.variants[key]; !ok {
+		fb.variantsOrder = append(fb.variantsOrder, key)
+	}
+	fb.variants[key] = value
+	return fb
+}
+
+func (fb *FlagBuilder) On(on bool) *FlagBuilder {
+	fb.on = on
+	return fb
+}
+
+func (fb *FlagBuilder) ClientSide(clientSide bool) *FlagBuilder {
+	fb.clientSide = clientSide
+	return fb
+}
+
+func (fb *FlagBuilder) OffVariation(value interface{}) *FlagBuilder {
+	fb.offVariant = fb.keyFor(value)
+	return fb
+}
+
+func (fb *FlagBuilder) FallthroughVariation(value interface{}) *FlagBuilder {
+	fb.fallthroughTraffic = map[string]int{fb.keyFor(value): TOTAL_TRAFFIC}
+	return fb
+}
+
+// FallthroughTraffic splits the users by variant key, the weights add up to 100000
+func (fb *FlagBuilder) FallthroughTraffic(traffic map[string]int) *FlagBuilder {
+	fb.fallthroughTraffic = copyTraffic(traffic)
+	return fb
+}
+
+func (fb *FlagBuilder) VariationForUser(userId string, value interface{}) *FlagBuilder {
+	fb.targetUsers[userId] = fb.keyFor(value)
+	return fb
+}
+
+func (fb *FlagBuilder) VariationForSegment(segmentId string, value interface{}) *FlagBuilder {
+	if _, ok := fb.targetSegments[segmentId]; !ok {
+		fb.targetSegmentsOrder = append(fb.targetSegmentsOrder, segmentId)
+	}
+	fb.targetSegments[segmentId] = map[string]int{fb.keyFor(value): TOTAL_TRAFFIC}
+	return fb
+}
+
+// PreRequisites serves the off variation to users outside any of the given segments
+func (fb *FlagBuilder) PreRequisites(segmentIds ...string) *FlagBuilder {
+	fb.preRequisites = append([]string{}, segmentIds...)
+	return fb
+}
+
+func (fb *FlagBuilder) ClearTargets() *FlagBuilder {
+	fb.targetUsers = map[string]string{}
+	fb.targetSegments = map[string]map[string]int{}
+	fb.targetSegmentsOrder = nil
+	return fb
+}
+
+// BucketBy splits the traffic by a user attribute instead of the userId
+func (fb *FlagBuilder) BucketBy(attribute string) *FlagBuilder {
+	fb.bucketBy = attribute
+	return fb
+}
+
+func (fb *FlagBuilder) Build() dto.FlagDTO {
+	status := dto.ACTIVE
+	if !fb.on {
+		status = dto.INACTIVE
+	}
+	variants := make(map[string]dto.Variant, len(fb.variants))
+	for key, value := range fb.variants {
+		variants[key] = dto.Variant{
+			Value: value,
+			Name:  key,
+		}
+	}
+	targetUsers := make(map[string]string, len(fb.targetUsers))
+	for key, value := range fb.targetUsers {
+		targetUsers[key] = value
+	}
+	targetSegments := make(map[string]map[string]int, len(fb.targetSegments))
+	for key, value := range fb.targetSegments {
+		targetSegments[key] = copyTraffic(value)
+	}
+	return dto.FlagDTO{
+		ID:            fb.id,
+		Variants:      variants,
+		VariantsOrder: append([]string{}, fb.variantsOrder...),
+		Type:          fb.variantType,
+		ClientSide:    fb.clientSide,
+		BucketBy:      fb.bucketBy,
+		EnvData: dto.EnvData{
+			PreRequisites:       append([]string{}, fb.preRequisites...),
+			OffVariant:          fb.offVariant,
+			TargetUsers:         targetUsers,
+			TargetSegments:      targetSegments,
+			TargetSegmentsOrder: append([]string{}, fb.targetSegmentsOrder...),
+			Traffic:             copyTraffic(fb.fallthroughTraffic),
+			Status:              status,
+		},
+	}
+}
+
+func (fb *FlagBuilder) reset(variantType string) {
+	fb.variantType = variantType
+	fb.variants = map[string]interface{}{}
+	fb.variantsOrder = nil
+	fb.on = true
+	fb.offVariant = ""
+	fb.bucketBy = ""
+	fb.fallthroughTraffic = map[string]int{}
+	fb.preRequisites = nil
+	fb.ClearTargets()
+}
+
+func (fb *FlagBuilder) keyFor(value interface{}) string {
+	for _, key := range fb.variantsOrder {
+		if reflect.DeepEqual(fb.variants[key], value) {
+			return key
+		}
+	}
+	key := fmt.Sprint(value)
+	if fb.variantType == dto.JSON {
+		key = fmt.Sprintf("variant%d", len(fb.variantsOrder))
+	}
+	fb.Variant(key, value)
+	return key
+}
+
+func (fb *FlagBuilder) copy() *FlagBuilder {
+	flagCopy := *fb
+	flagCopy.variants = make(map[string]interface{}, len(fb.variants))
+	for key, value := range fb.variants {
+		flagCopy.variants[key] = value
+	}
+	flagCopy.variantsOrder = append([]string{}, fb.variantsOrder...)
+	flagCopy.fallthroughTraffic = copyTraffic(fb.fallthroughTraffic)
+	flagCopy.targetUsers = make(map[string]string, len(fb.targetUsers))
+	for key, value := range fb.targetUsers {
+		flagCopy.targetUsers[key] = value
+	}
+	flagCopy.targetSegments = make(map[string]map[string]int, len(fb.targetSegments))
+	for key, value := range fb.targetSegments {
+		flagCopy.targetSegments[key] = copyTraffic(value)
+	}
+	flagCopy.targetSegmentsOrder = append([]string{}, fb.targetSegmentsOrder...)
+	flagCopy.preRequisites = append([]string{}, fb.preRequisites...)
+	return &flagCopy
+}
+
+func copyTraffic(traffic map[string]int) map[string]int {
+	trafficCopy := make(map[string]int, len(traffic))
+	for key, value := range traffic {
+		trafficCopy[key] = value
+	}
+	return trafficCopy
+}
@@ -0,0 +1,107 @@
+package fstestdata
+
+import (
+	"encoding/json"
+	"github.com/flagsense/go-sdk/pkg/dto"
+	"github.com/flagsense/go-sdk/pkg/util"
+	"strconv"
+)
+
+const (
+	USER_ID_KEY = "id"
+)
+
+// SegmentBuilder builds a segment whose rules are AND-ed, each rule being an OR of conditions
+type SegmentBuilder struct {
+	id    string
+	rules [][]*dto.RulesDTO
+}
+
+func newSegmentBuilder(segmentId string) *SegmentBuilder {
+	return &SegmentBuilder{
+		id: segmentId,
+	}
+}
+
+// Users matches the given user ids
+func (sb *SegmentBuilder) Users(userIds ...string) *SegmentBuilder {
+	values := make([]interface{}, len(userIds))
+	for i, userId := range userIds {
+		values[i] = userId
+	}
+	return sb.Rule(USER_ID_KEY, dto.STRING, dto.IOF, values...)
+}
+
+// Rule adds a new condition that must match in addition to the previous rules
+func (sb *SegmentBuilder) Rule(key string, ruleType string, operator string, values ...interface{}) *SegmentBuilder {
+	sb.rules = append(sb.rules, []*dto.RulesDTO{newRule(key, ruleType, operator, values)})
+	return sb
+}
+
+// NotRule adds a new condition that the user must not match, such as a MATCHES rule matching no pattern
+func (sb *SegmentBuilder) NotRule(key string, ruleType string, operator string, values ...interface{}) *SegmentBuilder {
+	rule := newRule(key, ruleType, operator, values)
+	rule.Match = false
+	sb.rules = append(sb.rules, []*dto.RulesDTO{rule})
+	return sb
+}
+
+// OrRule adds an alternative condition to the last rule
+func (sb *SegmentBuilder) OrRule(key string, ruleType string, operator string, values ...interface{}) *SegmentBuilder {
+	if len(sb.rules) == 0 {
+		return sb.Rule(key, ruleType, operator, values...)
+	}
+	last := len(sb.rules) - 1
+	sb.rules[last] = append(sb.rules[last], newRule(key, ruleType, operator, values))
+	return sb
+}
+
+// MatchAll makes the last added condition match a list attribute only when every element matches
+func (sb *SegmentBuilder) MatchAll() *SegmentBuilder {
+	if len(sb.rules) == 0 {
+		return sb
+	}
+	orRules := sb.rules[len(sb.rules)-1]
+	orRules[len(orRules)-1].MatchAll = true
+	return sb
+}
+
+func (sb *SegmentBuilder) Build() dto.SegmentDTO {
+	rules := make([][]*dto.RulesDTO, len(sb.rules))
+	for i, orRules := range sb.rules {
+		rules[i] = make([]*dto.RulesDTO, len(orRules))
+		for j, rule := range orRules {
+			ruleCopy := *rule
+			rules[i][j] = &ruleCopy
+		}
+	}
+	segment := dto.SegmentDTO{
+		ID:    sb.id,
+		Rules: rules,
+	}
+	segment.MatchType()
+	return segment
+}
+
+func newRule(key string, ruleType string, operator string, values []interface{}) *dto.RulesDTO {
+	// numbers are passed on as json.Number, the same as when the segment is parsed from json
+	jsonValues := make([]interface{}, len(values))
+	for i, value := range values {
+		switch value := value.(type) {
+		case int, int32, int64:
+			converted, _ := util.ToInt64(value)
+			jsonValues[i] = json.Number(strconv.FormatInt(converted, 10))
+		case float32, float64:
+			jsonValues[i] = json.Number(strconv.FormatFloat(util.ConvertToFloat64(value), 'g', -1, 64))
+		default:
+			jsonValues[i] = value
+		}
+	}
+	return &dto.RulesDTO{
+		Key:      key,
+		Match:    true,
+		Operator: operator,
+		Type:     ruleType,
+		Values:   jsonValues,
+	}
+}
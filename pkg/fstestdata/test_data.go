@@ -0,0 +1,124 @@
+package fstestdata
+
+import (
+	"context"
+	"github.com/flagsense/go-sdk/constants"
+	"github.com/flagsense/go-sdk/pkg/dto"
+	"github.com/flagsense/go-sdk/pkg/enums"
+	"github.com/flagsense/go-sdk/pkg/services"
+	"github.com/flagsense/go-sdk/pkg/services/impl"
+	"sync"
+)
+
+const (
+	TEST_SDK_ID     = "test-sdk-id"
+	TEST_SDK_SECRET = "test-sdk-secret"
+)
+
+// TestData is an in-memory source of flags data for unit tests. Every Update is pushed synchronously
+// to all services created from it, so the change is visible to the very next evaluation.
+type TestData struct {
+	lock          *sync.Mutex
+	flagBuilders  map[string]*FlagBuilder
+	flags         map[string]dto.FlagDTO
+	segments      map[string]dto.SegmentDTO
+	lastUpdatedOn float64
+	updaters      []services.DataSourceUpdater
+}
+
+type testDataSource struct {
+}
+
+func New() *TestData {
+	return &TestData{
+		lock:         &sync.Mutex{},
+		flagBuilders: map[string]*FlagBuilder{},
+		flags:        map[string]dto.FlagDTO{},
+		segments:     map[string]dto.SegmentDTO{},
+	}
+}
+
+// Flag returns a copy of the builder last passed to Update for the flag, or a new boolean flag
+// serving true to everyone. Changes are applied only once the builder is passed to Update.
+func (td *TestData) Flag(flagId string) *FlagBuilder {
+	td.lock.Lock()
+	defer td.lock.Unlock()
+	if builder, ok := td.flagBuilders[flagId]; ok {
+		return builder.copy()
+	}
+	return newFlagBuilder(flagId)
+}
+
+func (td *TestData) Segment(segmentId string) *SegmentBuilder {
+	return newSegmentBuilder(segmentId)
+}
+
+func (td *TestData) Update(builder *FlagBuilder) {
+	td.lock.Lock()
+	defer td.lock.Unlock()
+	td.flagBuilders[builder.id] = builder.copy()
+	td.flags[builder.id] = builder.Build()
+	td.push()
+}
+
+func (td *TestData) UpdateSegment(builder *SegmentBuilder) {
+	td.lock.Lock()
+	defer td.lock.Unlock()
+	td.segments[builder.id] = builder.Build()
+	td.push()
+}
+
+func (td *TestData) CreateDataSource(updater services.DataSourceUpdater) services.DataPollerService {
+	td.lock.Lock()
+	defer td.lock.Unlock()
+	td.updaters = append(td.updaters, updater)
+	td.pushTo(updater, td.snapshot())
+	return &testDataSource{}
+}
+
+// NewService creates a flagsense service that only reads from this TestData and never sends events
+func (td *TestData) NewService() services.FlagsenseService {
+	captureEvents := false
+	return impl.NewFlagsenseServiceWithOptions(TEST_SDK_ID, TEST_SDK_SECRET, enums.NewEnvironment(constants.DEV), impl.Options{
+		CaptureEvents:     &captureEvents,
+		DataSourceFactory: td,
+	})
+}
+
+func (td *TestData) push() {
+	td.lastUpdatedOn++
+	data := td.snapshot()
+	for _, updater := range td.updaters {
+		td.pushTo(updater, data)
+	}
+}
+
+func (td *TestData) snapshot() *dto.Data {
+	flags := make(map[string]dto.FlagDTO, len(td.flags))
+	for key, value := range td.flags {
+		flags[key] = value
+	}
+	segments := make(map[string]dto.SegmentDTO, len(td.segments))
+	for key, value := range td.segments {
+		segments[key] = value
+	}
+	lastUpdatedOn := td.lastUpdatedOn
+	if lastUpdatedOn == 0 {
+		// an empty TestData is still initialized
+		lastUpdatedOn = 1
+	}
+	return &dto.Data{
+		Segments:      segments,
+		Flags:         flags,
+		LastUpdatedOn: lastUpdatedOn,
+	}
+}
+
+func (td *TestData) pushTo(updater services.DataSourceUpdater, data *dto.Data) {
+	updater.Update(func(current *dto.Data) (*dto.Data, error) {
+		return data, nil
+	})
+}
+
+func (tds *testDataSource) Start(ctx context.Context) {
+}
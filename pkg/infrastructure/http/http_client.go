@@ -10,9 +10,60 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 )
 
+type HttpStatusError struct {
+	StatusCode int
+	Url        string
+	Body       interface{}
+	RetryAfter time.Duration
+}
+
+func (e *HttpStatusError) Error() string {
+	return fmt.Sprintf("non 200 response received. statusCode=%d, url=%s, err=%v", e.StatusCode, e.Url, e.Body)
+}
+
+// IsUnrecoverable is true when retrying can not help, e.g. for a revoked sdk secret
+func (e *HttpStatusError) IsUnrecoverable() bool {
+	return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+}
+
+func (e *HttpStatusError) IsRetriable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+func NewHttpStatusError(response *http.Response, url string, body interface{}) *HttpStatusError {
+	return &HttpStatusError{
+		StatusCode: response.StatusCode,
+		Url:        url,
+		Body:       body,
+		RetryAfter: parseRetryAfter(response.Header.Get("Retry-After")),
+	}
+}
+
+// parseRetryAfter accepts both forms of the header, delay seconds and an http date
+func parseRetryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay
+		}
+	}
+	return 0
+}
+
 func NewFlagSenseHttpClient() *http.Client {
 	return &http.Client{
 		Timeout: 5000 * time.Millisecond,
@@ -42,7 +93,7 @@ func MakeHttpRequest(ctx context.Context, method string, url string, client *htt
 
 	response, err := client.Do(req.WithContext(ctx))
 	if err != nil || response == nil {
-		return nil, fmt.Errorf("error in sending request to API endpoint. err=%+v, url=%s", err, url)
+		return nil, fmt.Errorf("error in sending request to API endpoint. err=%w, url=%s", err, url)
 	}
 
 	// Close the connection to reuse it
@@ -50,7 +101,7 @@ func MakeHttpRequest(ctx context.Context, method string, url string, client *htt
 	if response.StatusCode != 200 {
 		var target interface{}
 		body := json.NewDecoder(response.Body).Decode(target)
-		return nil, errors.New(fmt.Sprintf("non 200 response received. statusCode=%d, url=%s, err=%v", response.StatusCode, url, body))
+		return nil, NewHttpStatusError(response, url, body)
 	}
 
 	// Let's check if the work actually is done
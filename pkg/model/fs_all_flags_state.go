@@ -0,0 +1,36 @@
+package model
+
+type FSAllFlagsStateOptions struct {
+	ClientSideOnly bool
+	WithReasons    bool
+}
+
+type FSFlagState struct {
+	Key    string              `json:"key"`
+	Value  interface{}         `json:"value"`
+	Reason *FSEvaluationReason `json:"reason,omitempty"`
+}
+
+// FSAllFlagsState is the evaluation of every flag for one user, it can be handed to a frontend as json
+type FSAllFlagsState struct {
+	Valid         bool                   `json:"valid"`
+	LastUpdatedOn float64                `json:"lastUpdatedOn"`
+	Flags         map[string]FSFlagState `json:"flags"`
+}
+
+func (s FSAllFlagsState) GetFlagState(flagId string) (FSFlagState, bool) {
+	flagState, ok := s.Flags[flagId]
+	return flagState, ok
+}
+
+func (s FSAllFlagsState) GetFlagValue(flagId string) interface{} {
+	return s.Flags[flagId].Value
+}
+
+func (s FSAllFlagsState) ToValuesMap() map[string]interface{} {
+	values := make(map[string]interface{}, len(s.Flags))
+	for flagId, flagState := range s.Flags {
+		values[flagId] = flagState.Value
+	}
+	return values
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	DATA_SOURCE_INITIALIZING = "INITIALIZING"
+	DATA_SOURCE_VALID        = "VALID"
+	DATA_SOURCE_INTERRUPTED  = "INTERRUPTED"
+	DATA_SOURCE_FAILED       = "FAILED"
+	DATA_SOURCE_OFF          = "OFF"
+)
+
+var ErrDataSourceFailed = errors.New("flagsense data source failed permanently")
+
+// FSDataSourceStatus describes the health of the flags data. INTERRUPTED means the last known data is still served
+// while updates fail, FAILED that updates stopped for good, e.g. on bad credentials, and OFF that the service is closed.
+// Stale is set while the data comes from the local cache file and no fetch confirmed it yet.
+type FSDataSourceStatus struct {
+	State               string    `json:"state"`
+	StateSince          time.Time `json:"stateSince"`
+	LastSuccessfulFetch time.Time `json:"lastSuccessfulFetch"`
+	LastErrorKind       string    `json:"lastErrorKind,omitempty"`
+	StatusCode          int       `json:"statusCode,omitempty"`
+	LastError           string    `json:"lastError,omitempty"`
+	ConsecutiveFailures int       `json:"consecutiveFailures"`
+	Stale               bool      `json:"stale"`
+}
@@ -0,0 +1,48 @@
+package model
+
+const (
+	REASON_OFF                 = "OFF"
+	REASON_PREREQUISITE_FAILED = "PREREQUISITE_FAILED"
+	REASON_TARGET_MATCH        = "TARGET_MATCH"
+	REASON_SEGMENT_MATCH       = "SEGMENT_MATCH"
+	REASON_FALLTHROUGH         = "FALLTHROUGH"
+	REASON_ERROR               = "ERROR"
+
+	ERROR_CLIENT_NOT_READY   = "CLIENT_NOT_READY"
+	ERROR_FLAG_NOT_FOUND     = "FLAG_NOT_FOUND"
+	ERROR_USER_NOT_SPECIFIED = "USER_NOT_SPECIFIED"
+	ERROR_WRONG_TYPE         = "WRONG_TYPE"
+	ERROR_EXCEPTION          = "EXCEPTION"
+	ERROR_CONTEXT_DONE       = "CONTEXT_DONE"
+	ERROR_DECODE_FAILED      = "DECODE_FAILED"
+)
+
+type FSEvaluationReason struct {
+	Kind      string `json:"kind"`
+	SegmentId string `json:"segmentId,omitempty"`
+	ErrorKind string `json:"errorKind,omitempty"`
+	// BucketBy is the attribute a percentage rollout was bucketed by, empty when it was the userId
+	BucketBy string `json:"bucketBy,omitempty"`
+}
+
+type FSEvaluationDetail struct {
+	Key    string             `json:"key"`
+	Value  interface{}        `json:"value"`
+	Reason FSEvaluationReason `json:"reason"`
+}
+
+func NewReason(kind string) FSEvaluationReason {
+	return FSEvaluationReason{Kind: kind}
+}
+
+func NewSegmentMatchReason(segmentId string) FSEvaluationReason {
+	return FSEvaluationReason{Kind: REASON_SEGMENT_MATCH, SegmentId: segmentId}
+}
+
+func NewErrorReason(errorKind string) FSEvaluationReason {
+	return FSEvaluationReason{Kind: REASON_ERROR, ErrorKind: errorKind}
+}
+
+func (r FSEvaluationReason) IsError() bool {
+	return r.Kind == REASON_ERROR
+}
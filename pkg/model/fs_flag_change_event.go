@@ -0,0 +1,11 @@
+package model
+
+type FSFlagChangeEvent struct {
+	FlagId string
+}
+
+type FSFlagValueChangeEvent struct {
+	FlagId   string
+	OldValue FSVariation
+	NewValue FSVariation
+}
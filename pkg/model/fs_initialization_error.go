@@ -0,0 +1,30 @@
+package model
+
+import "fmt"
+
+const (
+	INIT_FAILURE_BAD_CREDENTIALS = "BAD_CREDENTIALS"
+	INIT_FAILURE_NETWORK         = "NETWORK"
+	INIT_FAILURE_HTTP_ERROR      = "HTTP_ERROR"
+	INIT_FAILURE_INVALID_DATA    = "INVALID_DATA"
+	INIT_FAILURE_UNKNOWN         = "UNKNOWN"
+)
+
+// FSInitializationError is returned when waiting for the first flags data is given up. Err is the reason the wait
+// ended, e.g. context.DeadlineExceeded, and Kind with LastError describe the last failed attempt to load the data.
+type FSInitializationError struct {
+	Kind      string
+	LastError error
+	Err       error
+}
+
+func (e *FSInitializationError) Error() string {
+	if e.LastError == nil {
+		return fmt.Sprintf("flagsense initialization did not complete: %v", e.Err)
+	}
+	return fmt.Sprintf("flagsense initialization did not complete: %v, last failure: %s, %v", e.Err, e.Kind, e.LastError)
+}
+
+func (e *FSInitializationError) Unwrap() error {
+	return e.Err
+}
@@ -1,6 +1,6 @@
 package model
 
 type FSUser struct {
-	UserId     string
-	Attributes map[string]interface{}
+	UserId     string                 `json:"userId"`
+	Attributes map[string]interface{} `json:"attributes"`
 }
@@ -1,10 +1,8 @@
 package model
 
 import (
-	"errors"
-	"github.com/flagsense/go-sdk/pkg/util"
 	"fmt"
-	"strings"
+	"github.com/flagsense/go-sdk/pkg/util"
 )
 
 type FSVariation struct {
@@ -13,30 +11,41 @@ type FSVariation struct {
 }
 
 func (fsv *FSVariation) ToBoolean() (FSVariation, error) {
-	if fmt.Sprintf("%T", fsv.Value) != "bool" {
-		return FSVariation{}, errors.New(fmt.Sprintf("Value is not boolean, %+v", fsv.Value))
+	value, ok := fsv.Value.(bool)
+	if !ok {
+		return FSVariation{}, fmt.Errorf("Value is not boolean, %+v", fsv.Value)
 	}
 	return FSVariation{
 		Key:   fsv.Key,
-		Value: fsv.Value.(bool),
+		Value: value,
 	}, nil
 }
 
 func (fsv *FSVariation) ToInteger() (FSVariation, error) {
-	valType := fmt.Sprintf("%T", fsv.Value)
-	if !strings.Contains(valType, "int") && !strings.Contains(valType, "float") {
-		return FSVariation{}, errors.New(fmt.Sprintf("Value is not int32, %+v", fsv.Value))
+	value, err := util.ToInt32(fsv.Value)
+	if err != nil {
+		return FSVariation{}, fmt.Errorf("Value is not int32, %+v: %v", fsv.Value, err)
+	}
+	return FSVariation{
+		Key:   fsv.Key,
+		Value: value,
+	}, nil
+}
+
+func (fsv *FSVariation) ToInt64() (FSVariation, error) {
+	value, err := util.ToInt64(fsv.Value)
+	if err != nil {
+		return FSVariation{}, fmt.Errorf("Value is not int64, %+v: %v", fsv.Value, err)
 	}
 	return FSVariation{
 		Key:   fsv.Key,
-		Value: util.ConvertToInt32(fsv.Value),
+		Value: value,
 	}, nil
 }
 
 func (fsv *FSVariation) ToDouble() (FSVariation, error) {
-	valType := fmt.Sprintf("%T", fsv.Value)
-	if !strings.Contains(valType, "int") && !strings.Contains(valType, "float") {
-		return FSVariation{}, errors.New(fmt.Sprintf("Value is not float64, %+v", fsv.Value))
+	if !util.IsNumber(fsv.Value) {
+		return FSVariation{}, fmt.Errorf("Value is not float64, %+v", fsv.Value)
 	}
 	return FSVariation{
 		Key:   fsv.Key,
@@ -45,21 +54,23 @@ func (fsv *FSVariation) ToDouble() (FSVariation, error) {
 }
 
 func (fsv *FSVariation) ToString() (FSVariation, error) {
-	if fmt.Sprintf("%T", fsv.Value) != "string" {
-		return FSVariation{}, errors.New(fmt.Sprintf("Value is not boolean, %+v", fsv.Value))
+	value, ok := fsv.Value.(string)
+	if !ok {
+		return FSVariation{}, fmt.Errorf("Value is not string, %+v", fsv.Value)
 	}
 	return FSVariation{
 		Key:   fsv.Key,
-		Value: fsv.Value.(string),
+		Value: value,
 	}, nil
 }
 
 func (fsv *FSVariation) ToMap() (FSVariation, error) {
-	if fmt.Sprintf("%T", fsv.Value) != "map[string]interface {}" {
-		return FSVariation{}, errors.New(fmt.Sprintf("Value is not boolean, %+v", fsv.Value))
+	value, ok := fsv.Value.(map[string]interface{})
+	if !ok {
+		return FSVariation{}, fmt.Errorf("Value is not map, %+v", fsv.Value)
 	}
 	return FSVariation{
 		Key:   fsv.Key,
-		Value: fsv.Value.(map[string]interface{}),
+		Value: value,
 	}, nil
 }
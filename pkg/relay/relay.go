@@ -0,0 +1,265 @@
+package relay
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/flagsense/go-sdk/config"
+	"github.com/flagsense/go-sdk/pkg/dto"
+	"github.com/flagsense/go-sdk/pkg/enums"
+	"github.com/flagsense/go-sdk/pkg/model"
+	"github.com/flagsense/go-sdk/pkg/services/impl"
+	"github.com/flagsense/go-sdk/third_party/assetmnger"
+	fslogger "github.com/flagsense/go-sdk/third_party/logger"
+	"github.com/teltech/logger"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	FETCH_LATEST_PATH  = "/fetchLatest"
+	VARIANTS_DATA_PATH = "/variantsData"
+	STATUS_PATH        = "/status"
+)
+
+// EnvironmentConfig is one sdk environment the relay fetches flags for. With a StoreFile the flags are also written to a file
+// feature store, which daemon mode sdk instances can read.
+type EnvironmentConfig struct {
+	SDKId       string
+	SDKSecret   string
+	Environment string
+	StoreFile   string
+}
+
+type Config struct {
+	Environments     []EnvironmentConfig
+	SDKServiceUrl    string
+	EventsServiceUrl string
+	Streaming        bool
+	PollingInterval  time.Duration
+	HttpClient       *http.Client
+	Logger           *logger.Log
+}
+
+// Relay keeps the flags of every configured sdk environment in memory and serves them to other sdk instances through
+// the same api as the flagsense servers, events are passed through to the events service.
+type Relay struct {
+	environments map[environmentKey]*relayEnvironment
+	eventsUrl    string
+	client       *http.Client
+	logger       *logger.Log
+}
+
+// environmentKey identifies a relayed environment, one sdk id may be relayed for several environments
+type environmentKey struct {
+	sdkId       string
+	environment string
+}
+
+type relayEnvironment struct {
+	config  EnvironmentConfig
+	service *impl.FlagsenseServiceImpl
+}
+
+type environmentStatus struct {
+	Environment string                   `json:"environment"`
+	Status      model.FSDataSourceStatus `json:"status"`
+}
+
+func NewRelay(relayConfig Config) (*Relay, error) {
+	if len(relayConfig.Environments) == 0 {
+		return nil, errors.New("no relay environments configured")
+	}
+	eventsUrl := strings.TrimRight(relayConfig.EventsServiceUrl, "/")
+	if strings.TrimSpace(eventsUrl) == "" {
+		eventsUrl = config.NewConfig(assetmnger.NewManager()).Services.EventsService.HttpEndpoint.Url
+	}
+	client := relayConfig.HttpClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	log := relayConfig.Logger
+	if log == nil {
+		log = fslogger.NewLogger()
+	}
+	relay := &Relay{
+		environments: map[environmentKey]*relayEnvironment{},
+		eventsUrl:    eventsUrl,
+		client:       client,
+		logger:       log,
+	}
+	for _, envConfig := range relayConfig.Environments {
+		if strings.TrimSpace(envConfig.SDKId) == "" || strings.TrimSpace(envConfig.SDKSecret) == "" {
+			relay.Close()
+			return nil, errors.New("empty sdk params not allowed")
+		}
+		environment := enums.NewEnvironment(envConfig.Environment)
+		if !environment.IsValid(envConfig.Environment) {
+			relay.Close()
+			return nil, fmt.Errorf("invalid environment %s for sdk %s", envConfig.Environment, envConfig.SDKId)
+		}
+		key := environmentKey{sdkId: envConfig.SDKId, environment: envConfig.Environment}
+		if _, ok := relay.environments[key]; ok {
+			relay.Close()
+			return nil, fmt.Errorf("sdk %s configured twice for %s", envConfig.SDKId, envConfig.Environment)
+		}
+
+		captureEvents := false
+		options := impl.Options{
+			SDKServiceUrl:   relayConfig.SDKServiceUrl,
+			Streaming:       relayConfig.Streaming,
+			PollingInterval: relayConfig.PollingInterval,
+			CaptureEvents:   &captureEvents,
+			HttpClient:      relayConfig.HttpClient,
+			Logger:          log,
+		}
+		if envConfig.StoreFile != "" {
+			options.FeatureStore = impl.NewFileFeatureStore(envConfig.StoreFile)
+		}
+		relay.environments[key] = &relayEnvironment{
+			config:  envConfig,
+			service: impl.NewFlagsenseServiceWithOptions(envConfig.SDKId, envConfig.SDKSecret, environment, options),
+		}
+	}
+	return relay, nil
+}
+
+func (r *Relay) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(FETCH_LATEST_PATH, r.fetchLatest)
+	mux.HandleFunc(VARIANTS_DATA_PATH, r.variantsData)
+	mux.HandleFunc(STATUS_PATH, r.status)
+	return mux
+}
+
+func (r *Relay) Close() {
+	for _, environment := range r.environments {
+		environment.service.Close()
+	}
+}
+
+// fetchLatest answers like the sdk service, a response without flags and segments tells that nothing changed
+func (r *Relay) fetchLatest(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	var request impl.DataPollerRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	environment, status := r.authenticate(req, request.Environment)
+	if status != http.StatusOK {
+		w.WriteHeader(status)
+		return
+	}
+	if !environment.service.InitializationComplete() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	data := environment.service.Store.All()
+	if request.LastUpdatedOn >= data.LastUpdatedOn {
+		data = &dto.Data{LastUpdatedOn: data.LastUpdatedOn}
+	}
+	writeJson(w, http.StatusOK, data)
+}
+
+// variantsData passes the events through with the credentials of the relay
+func (r *Relay) variantsData(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	var events impl.VariantsRequest
+	if err := json.Unmarshal(body, &events); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	environment, status := r.authenticate(req, events.Environment)
+	if status != http.StatusOK {
+		w.WriteHeader(status)
+		return
+	}
+
+	eventsRequest, err := http.NewRequestWithContext(req.Context(), http.MethodPost, r.eventsUrl+VARIANTS_DATA_PATH, bytes.NewReader(body))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	eventsRequest.Header.Set(impl.CONTENT_TYPE, impl.APPLICATION_JSON)
+	eventsRequest.Header.Set(impl.HEADER_AUTH_TYPE, impl.SDK)
+	eventsRequest.Header.Set(impl.HEADER_SDK_ID, environment.config.SDKId)
+	eventsRequest.Header.Set(impl.HEADER_SDK_SECRET, environment.config.SDKSecret)
+	response, err := r.client.Do(eventsRequest)
+	if err != nil {
+		r.logger.Errorf("error while passing events through, error:%+v", err)
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+	defer response.Body.Close()
+	responseBody, _ := ioutil.ReadAll(response.Body)
+	w.WriteHeader(response.StatusCode)
+	w.Write(responseBody)
+}
+
+// status reports the data source status of every sdk environment, keyed by sdkId:environment. It is unavailable
+// until all of them are initialized.
+func (r *Relay) status(w http.ResponseWriter, req *http.Request) {
+	statuses := map[string]environmentStatus{}
+	ready := true
+	for key, environment := range r.environments {
+		statuses[key.sdkId+":"+key.environment] = environmentStatus{
+			Environment: environment.config.Environment,
+			Status:      environment.service.DataSourceStatus(),
+		}
+		ready = ready && environment.service.InitializationComplete()
+	}
+	if !ready {
+		writeJson(w, http.StatusServiceUnavailable, statuses)
+		return
+	}
+	writeJson(w, http.StatusOK, statuses)
+}
+
+// authenticate selects the relayed environment of the request, it answers unauthorized for an unknown sdk id or
+// a wrong secret and bad request for an environment the sdk is not relayed for
+func (r *Relay) authenticate(req *http.Request, environmentName string) (*relayEnvironment, int) {
+	sdkId := req.Header.Get(impl.HEADER_SDK_ID)
+	environment, ok := r.environments[environmentKey{sdkId: sdkId, environment: environmentName}]
+	if !ok {
+		for key := range r.environments {
+			if key.sdkId == sdkId {
+				return nil, http.StatusBadRequest
+			}
+		}
+		return nil, http.StatusUnauthorized
+	}
+	secret := req.Header.Get(impl.HEADER_SDK_SECRET)
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(environment.config.SDKSecret)) != 1 {
+		return nil, http.StatusUnauthorized
+	}
+	return environment, http.StatusOK
+}
+
+// writeJson encodes the value before writing anything, so a failed encoding can still answer with an error status
+func writeJson(w http.ResponseWriter, status int, value interface{}) {
+	content, err := json.Marshal(value)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set(impl.CONTENT_TYPE, impl.APPLICATION_JSON)
+	w.WriteHeader(status)
+	w.Write(content)
+}
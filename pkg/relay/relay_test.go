@@ -0,0 +1,165 @@
+package relay_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/flagsense/go-sdk/pkg/dto"
+	"github.com/flagsense/go-sdk/pkg/relay"
+	"github.com/flagsense/go-sdk/pkg/services/impl"
+)
+
+// lastUpdatedOn tells the environments served by the upstream apart
+var lastUpdatedOn = map[string]float64{"DEV": 10, "PROD": 20}
+
+// upstream stands in for the sdk and events services of flagsense
+type upstream struct {
+	*httptest.Server
+	events chan *http.Request
+}
+
+func newUpstream() *upstream {
+	u := &upstream{events: make(chan *http.Request, 10)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/fetchLatest", func(w http.ResponseWriter, req *http.Request) {
+		var request impl.DataPollerRequest
+		json.NewDecoder(req.Body).Decode(&request)
+		json.NewEncoder(w).Encode(dto.Data{
+			Flags:         map[string]dto.FlagDTO{"flag": {ID: "flag", Type: dto.BOOL}},
+			Segments:      map[string]dto.SegmentDTO{},
+			LastUpdatedOn: lastUpdatedOn[request.Environment],
+		})
+	})
+	mux.HandleFunc("/variantsData", func(w http.ResponseWriter, req *http.Request) {
+		u.events <- req
+		w.WriteHeader(http.StatusAccepted)
+	})
+	u.Server = httptest.NewServer(mux)
+	return u
+}
+
+func newRelay(t *testing.T, upstream *upstream) *httptest.Server {
+	flagsRelay, err := relay.NewRelay(relay.Config{
+		Environments: []relay.EnvironmentConfig{
+			{SDKId: "sdk", SDKSecret: "secret", Environment: "DEV"},
+			{SDKId: "sdk", SDKSecret: "prod:secret", Environment: "PROD"},
+		},
+		SDKServiceUrl:    upstream.URL,
+		EventsServiceUrl: upstream.URL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(flagsRelay.Handler())
+	t.Cleanup(func() {
+		server.Close()
+		flagsRelay.Close()
+	})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		response, err := http.Get(server.URL + relay.STATUS_PATH)
+		if err != nil {
+			t.Fatal(err)
+		}
+		response.Body.Close()
+		if response.StatusCode == http.StatusOK {
+			return server
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("relay not ready, status %d", response.StatusCode)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func post(t *testing.T, url string, sdkId string, secret string, body string) (*http.Response, []byte) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(impl.HEADER_SDK_ID, sdkId)
+	req.Header.Set(impl.HEADER_SDK_SECRET, secret)
+	response, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	content, _ := ioutil.ReadAll(response.Body)
+	return response, content
+}
+
+func TestRelayFetchLatest(t *testing.T) {
+	upstream := newUpstream()
+	defer upstream.Close()
+	server := newRelay(t, upstream)
+
+	tests := []struct {
+		name          string
+		sdkId         string
+		secret        string
+		body          string
+		status        int
+		lastUpdatedOn float64
+		withFlags     bool
+	}{
+		{"outdated data", "sdk", "secret", `{"environment":"DEV","lastUpdatedOn":0}`, http.StatusOK, 10, true},
+		{"up to date data", "sdk", "secret", `{"environment":"DEV","lastUpdatedOn":10}`, http.StatusOK, 10, false},
+		{"second environment", "sdk", "prod:secret", `{"environment":"PROD","lastUpdatedOn":10}`, http.StatusOK, 20, true},
+		{"unknown sdk", "other", "secret", `{"environment":"DEV"}`, http.StatusUnauthorized, 0, false},
+		{"wrong secret", "sdk", "wrong", `{"environment":"DEV"}`, http.StatusUnauthorized, 0, false},
+		{"secret of another environment", "sdk", "secret", `{"environment":"PROD"}`, http.StatusUnauthorized, 0, false},
+		{"other environment", "sdk", "secret", `{"environment":"STAG"}`, http.StatusBadRequest, 0, false},
+		{"broken request", "sdk", "secret", `{environment`, http.StatusBadRequest, 0, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response, content := post(t, server.URL+relay.FETCH_LATEST_PATH, test.sdkId, test.secret, test.body)
+			if response.StatusCode != test.status {
+				t.Fatalf("expected status %d, got %d", test.status, response.StatusCode)
+			}
+			if test.status != http.StatusOK {
+				return
+			}
+			var data dto.Data
+			if err := json.Unmarshal(content, &data); err != nil {
+				t.Fatal(err)
+			}
+			if data.LastUpdatedOn != test.lastUpdatedOn || (data.Flags != nil) != test.withFlags {
+				t.Fatalf("unexpected data %s", content)
+			}
+		})
+	}
+}
+
+func TestRelayPassesEventsThrough(t *testing.T) {
+	upstream := newUpstream()
+	defer upstream.Close()
+	server := newRelay(t, upstream)
+
+	for _, environment := range []relay.EnvironmentConfig{{SDKSecret: "secret", Environment: "DEV"}, {SDKSecret: "prod:secret", Environment: "PROD"}} {
+		body := `{"environment":"` + environment.Environment + `","data":{}}`
+		response, _ := post(t, server.URL+relay.VARIANTS_DATA_PATH, "sdk", environment.SDKSecret, body)
+		if response.StatusCode != http.StatusAccepted {
+			t.Fatalf("expected the events service status, got %d", response.StatusCode)
+		}
+		select {
+		case req := <-upstream.events:
+			if req.Header.Get(impl.HEADER_SDK_ID) != "sdk" || req.Header.Get(impl.HEADER_SDK_SECRET) != environment.SDKSecret {
+				t.Fatalf("events passed with wrong credentials %v", req.Header)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("events not passed through")
+		}
+	}
+
+	if response, _ := post(t, server.URL+relay.VARIANTS_DATA_PATH, "sdk", "wrong", `{"environment":"DEV"}`); response.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected unauthorized, got %d", response.StatusCode)
+	}
+}
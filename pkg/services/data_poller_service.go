@@ -1,8 +1,9 @@
 package services
 
-import "context"
+import (
+	"context"
+)
 
 type DataPollerService interface {
 	Start(ctx context.Context)
-	WaitForInitializationComplete()
 }
@@ -0,0 +1,5 @@
+package services
+
+type DataSourceFactory interface {
+	CreateDataSource(updater DataSourceUpdater) DataPollerService
+}
@@ -0,0 +1,9 @@
+package services
+
+import "github.com/flagsense/go-sdk/pkg/model"
+
+type DataSourceStatusProvider interface {
+	GetStatus() model.FSDataSourceStatus
+	AddStatusListener() <-chan model.FSDataSourceStatus
+	RemoveStatusListener(listener <-chan model.FSDataSourceStatus)
+}
@@ -0,0 +1,14 @@
+package services
+
+import (
+	"github.com/flagsense/go-sdk/pkg/dto"
+	"time"
+)
+
+// DataSourceUpdater is how a data source hands new flags data and its failures to the service
+type DataSourceUpdater interface {
+	Current() *dto.Data
+	Update(modify func(current *dto.Data) (*dto.Data, error)) error
+	SetError(err error)
+	LoadCached(data *dto.Data, savedOn time.Time) error
+}
@@ -0,0 +1,19 @@
+package services
+
+import "github.com/flagsense/go-sdk/pkg/dto"
+
+// FeatureStore holds the flags data evaluated by the sdk. Data sources write it through Init and Upsert,
+// the data returned by the getters must never be modified.
+type FeatureStore interface {
+	Init(data *dto.Data) error
+	GetFlag(flagId string) (dto.FlagDTO, bool)
+	GetSegment(segmentId string) (dto.SegmentDTO, bool)
+	All() *dto.Data
+	Upsert(data *dto.Data) error
+	IsInitialized() bool
+}
+
+// FeatureStoreObserver is implemented by stores which report their changes, the flag change listeners need it
+type FeatureStoreObserver interface {
+	SetUpdateListener(listener func(previous *dto.Data, current *dto.Data))
+}
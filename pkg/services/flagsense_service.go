@@ -1,15 +1,48 @@
 package services
 
-import "github.com/flagsense/go-sdk/pkg/model"
+import (
+	"context"
+	"github.com/flagsense/go-sdk/pkg/model"
+)
 
 type FlagsenseService interface {
 	InitializationComplete() bool
 	WaitForInitializationComplete()
+	WaitForInitialization(ctx context.Context) error
+	InitializationError() error
+	DataSourceStatus() model.FSDataSourceStatus
+	GetDataSourceStatusProvider() DataSourceStatusProvider
 	Close()
 	BooleanVariation(fsFlag model.FSFlag, user model.FSUser) model.FSVariation
 	StringVariation(fsFlag model.FSFlag, user model.FSUser) model.FSVariation
 	IntegerVariation(fsFlag model.FSFlag, user model.FSUser) model.FSVariation
 	DecimalVariation(fsFlag model.FSFlag, user model.FSUser) model.FSVariation
 	MapVariation(fsFlag model.FSFlag, user model.FSUser) model.FSVariation
+	BooleanVariationDetail(fsFlag model.FSFlag, user model.FSUser) model.FSEvaluationDetail
+	StringVariationDetail(fsFlag model.FSFlag, user model.FSUser) model.FSEvaluationDetail
+	IntegerVariationDetail(fsFlag model.FSFlag, user model.FSUser) model.FSEvaluationDetail
+	DecimalVariationDetail(fsFlag model.FSFlag, user model.FSUser) model.FSEvaluationDetail
+	MapVariationDetail(fsFlag model.FSFlag, user model.FSUser) model.FSEvaluationDetail
+	BooleanVariationCtx(ctx context.Context, fsFlag model.FSFlag, user model.FSUser) model.FSVariation
+	StringVariationCtx(ctx context.Context, fsFlag model.FSFlag, user model.FSUser) model.FSVariation
+	IntegerVariationCtx(ctx context.Context, fsFlag model.FSFlag, user model.FSUser) model.FSVariation
+	DecimalVariationCtx(ctx context.Context, fsFlag model.FSFlag, user model.FSUser) model.FSVariation
+	MapVariationCtx(ctx context.Context, fsFlag model.FSFlag, user model.FSUser) model.FSVariation
+	BooleanVariationDetailCtx(ctx context.Context, fsFlag model.FSFlag, user model.FSUser) model.FSEvaluationDetail
+	StringVariationDetailCtx(ctx context.Context, fsFlag model.FSFlag, user model.FSUser) model.FSEvaluationDetail
+	IntegerVariationDetailCtx(ctx context.Context, fsFlag model.FSFlag, user model.FSUser) model.FSEvaluationDetail
+	DecimalVariationDetailCtx(ctx context.Context, fsFlag model.FSFlag, user model.FSUser) model.FSEvaluationDetail
+	MapVariationDetailCtx(ctx context.Context, fsFlag model.FSFlag, user model.FSUser) model.FSEvaluationDetail
+	Int64Variation(fsFlag model.FSFlag, user model.FSUser) model.FSVariation
+	Int64VariationDetail(fsFlag model.FSFlag, user model.FSUser) model.FSEvaluationDetail
+	Int64VariationCtx(ctx context.Context, fsFlag model.FSFlag, user model.FSUser) model.FSVariation
+	Int64VariationDetailCtx(ctx context.Context, fsFlag model.FSFlag, user model.FSUser) model.FSEvaluationDetail
+	JSONVariation(fsFlag model.FSFlag, user model.FSUser, out interface{}) model.FSEvaluationDetail
+	JSONVariationCtx(ctx context.Context, fsFlag model.FSFlag, user model.FSUser, out interface{}) model.FSEvaluationDetail
+	AllFlagsState(user model.FSUser, options model.FSAllFlagsStateOptions) model.FSAllFlagsState
+	AddFlagChangeListener() <-chan model.FSFlagChangeEvent
+	RemoveFlagChangeListener(listener <-chan model.FSFlagChangeEvent)
+	AddFlagValueChangeListener(flagId string, user model.FSUser) <-chan model.FSFlagValueChangeEvent
+	RemoveFlagValueChangeListener(listener <-chan model.FSFlagValueChangeEvent)
 	RecordCodeError(flagId string, variationKey string)
 }
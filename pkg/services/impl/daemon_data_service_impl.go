@@ -0,0 +1,76 @@
+package impl
+
+import (
+	"context"
+	"github.com/flagsense/go-sdk/pkg/dto"
+	"github.com/flagsense/go-sdk/pkg/services"
+	"time"
+)
+
+const (
+	DAEMON_REFRESH_INTERVAL = 5 * time.Second
+)
+
+type refreshableFeatureStore interface {
+	Refresh() error
+}
+
+// DaemonDataServiceImpl never fetches flags data, it reads a feature store populated by another process.
+// Stores which support it are refreshed on every tick.
+type DaemonDataServiceImpl struct {
+	Store           services.FeatureStore
+	RefreshInterval time.Duration
+	services.DataSourceUpdater
+}
+
+func NewDaemonDataService(store services.FeatureStore, refreshInterval time.Duration,
+	updater services.DataSourceUpdater) *DaemonDataServiceImpl {
+	if refreshInterval <= 0 {
+		refreshInterval = DAEMON_REFRESH_INTERVAL
+	}
+	return &DaemonDataServiceImpl{
+		Store:             store,
+		RefreshInterval:   refreshInterval,
+		DataSourceUpdater: updater,
+	}
+}
+
+func (dds *DaemonDataServiceImpl) Start(ctx context.Context) {
+	// nothing else writes to an in-memory store, waiting for its data would never end
+	if !isPersistentStore(dds.Store) {
+		dds.SetError(&unrecoverableError{err: ErrDaemonModeWithoutStore})
+		return
+	}
+	dds.refresh()
+	tick := time.NewTicker(dds.RefreshInterval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-tick.C:
+			dds.refresh()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (dds *DaemonDataServiceImpl) refresh() {
+	if store, ok := dds.Store.(refreshableFeatureStore); ok {
+		if err := store.Refresh(); err != nil && !dds.Store.IsInitialized() {
+			dds.SetError(err)
+			return
+		}
+	}
+	if !dds.Store.IsInitialized() {
+		return
+	}
+	// the store is written elsewhere, the update only confirms that its data is available
+	dds.Update(func(current *dto.Data) (*dto.Data, error) {
+		return nil, nil
+	})
+}
+
+func isPersistentStore(store services.FeatureStore) bool {
+	_, inMemory := store.(*InMemoryFeatureStoreImpl)
+	return store != nil && !inMemory
+}
@@ -0,0 +1,73 @@
+package impl_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/flagsense/go-sdk/client"
+	"github.com/flagsense/go-sdk/constants"
+	"github.com/flagsense/go-sdk/pkg/dto"
+	"github.com/flagsense/go-sdk/pkg/enums"
+	"github.com/flagsense/go-sdk/pkg/model"
+	"github.com/flagsense/go-sdk/pkg/services"
+	"github.com/flagsense/go-sdk/pkg/services/impl"
+)
+
+func newDaemonService(store services.FeatureStore) services.FlagsenseService {
+	captureEvents := false
+	return impl.NewFlagsenseServiceWithOptions("daemon", "secret", enums.NewEnvironment(constants.DEV), impl.Options{
+		DaemonMode:    true,
+		FeatureStore:  store,
+		CaptureEvents: &captureEvents,
+	})
+}
+
+func waitForInitialization(t *testing.T, service services.FlagsenseService) {
+	initialized := make(chan struct{})
+	go func() {
+		service.WaitForInitializationComplete()
+		close(initialized)
+	}()
+	waitFor(t, "initialization", initialized)
+}
+
+func TestDaemonModeReadsTheFileStore(t *testing.T) {
+	storeFile := filepath.Join(t.TempDir(), "store.json")
+	err := impl.NewFileFeatureStore(storeFile).Init(&dto.Data{
+		Flags:         map[string]dto.FlagDTO{"flag": stringFlag("b")},
+		Segments:      map[string]dto.SegmentDTO{},
+		LastUpdatedOn: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	service := newDaemonService(impl.NewFileFeatureStore(storeFile))
+	defer service.Close()
+	waitForInitialization(t, service)
+	expectValue(t, service, "b")
+}
+
+func TestDaemonModeWithoutPersistentStore(t *testing.T) {
+	for name, store := range map[string]services.FeatureStore{
+		"no store":        nil,
+		"in-memory store": impl.NewInMemoryFeatureStore(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			service := newDaemonService(store)
+			defer service.Close()
+			waitForInitialization(t, service)
+			if status := service.DataSourceStatus(); status.State != model.DATA_SOURCE_FAILED {
+				t.Fatalf("expected FAILED, got %s", status.State)
+			}
+			if err := service.InitializationError(); !errors.Is(err, impl.ErrDaemonModeWithoutStore) {
+				t.Fatalf("expected the configuration error, got %v", err)
+			}
+		})
+	}
+
+	if err, _ := client.CreateServiceWithOptions("daemon", "secret", "DEV", client.WithDaemonMode(true)); !errors.Is(err, impl.ErrDaemonModeWithoutStore) {
+		t.Fatalf("expected the client to refuse daemon mode, got %v", err)
+	}
+}
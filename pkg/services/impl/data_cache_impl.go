@@ -0,0 +1,121 @@
+package impl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/flagsense/go-sdk/pkg/dto"
+	"github.com/flagsense/go-sdk/pkg/model"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	DATA_CACHE_VERSION = 1
+)
+
+// DataCacheImpl keeps the last good flags data on disk, so a restart can serve it before the first fetch succeeds.
+// Without SDKConfig the file is not bound to an sdk and environment.
+type DataCacheImpl struct {
+	FilePath  string
+	SDKConfig *model.SDKConfig
+}
+
+type dataCacheFile struct {
+	Version     int             `json:"version"`
+	SDKId       string          `json:"sdkId"`
+	Environment string          `json:"environment"`
+	SavedOn     int64           `json:"savedOn"`
+	Checksum    string          `json:"checksum"`
+	Data        json.RawMessage `json:"data"`
+}
+
+func NewDataCache(filePath string, sdkConfig *model.SDKConfig) *DataCacheImpl {
+	return &DataCacheImpl{
+		FilePath:  filePath,
+		SDKConfig: sdkConfig,
+	}
+}
+
+// Save writes to a temporary file next to the cache file and renames it, so readers never see a partial file
+func (dc *DataCacheImpl) Save(data *dto.Data) error {
+	dataBytes, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	cacheFile := dataCacheFile{
+		Version:  DATA_CACHE_VERSION,
+		SavedOn:  time.Now().UnixNano() / int64(time.Millisecond),
+		Checksum: checksum(dataBytes),
+		Data:     dataBytes,
+	}
+	if dc.SDKConfig != nil {
+		cacheFile.SDKId = dc.SDKConfig.SDKId
+		cacheFile.Environment = dc.SDKConfig.Environment
+	}
+	content, err := json.Marshal(cacheFile)
+	if err != nil {
+		return err
+	}
+
+	tempFile, err := ioutil.TempFile(filepath.Dir(dc.FilePath), filepath.Base(dc.FilePath)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempFile.Name())
+	if _, err := tempFile.Write(content); err != nil {
+		tempFile.Close()
+		return err
+	}
+	if err := tempFile.Sync(); err != nil {
+		tempFile.Close()
+		return err
+	}
+	if err := tempFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tempFile.Name(), dc.FilePath)
+}
+
+// Load returns the cached data with the time it was saved, it fails for a cache of another sdk or environment
+func (dc *DataCacheImpl) Load() (*dto.Data, time.Time, error) {
+	content, err := ioutil.ReadFile(dc.FilePath)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	var cacheFile dataCacheFile
+	if err := json.Unmarshal(content, &cacheFile); err != nil {
+		return nil, time.Time{}, err
+	}
+	if cacheFile.Version != DATA_CACHE_VERSION {
+		return nil, time.Time{}, fmt.Errorf("unsupported flags cache version: %d", cacheFile.Version)
+	}
+	if dc.SDKConfig != nil && (cacheFile.SDKId != dc.SDKConfig.SDKId || cacheFile.Environment != dc.SDKConfig.Environment) {
+		return nil, time.Time{}, errors.New("flags cache belongs to another sdk or environment")
+	}
+	if cacheFile.Checksum != checksum(cacheFile.Data) {
+		return nil, time.Time{}, errors.New("flags cache checksum mismatch")
+	}
+
+	var data dto.Data
+	if err := json.Unmarshal(cacheFile.Data, &data); err != nil {
+		return nil, time.Time{}, err
+	}
+	if data.Flags == nil {
+		data.Flags = map[string]dto.FlagDTO{}
+	}
+	if data.Segments == nil {
+		data.Segments = map[string]dto.SegmentDTO{}
+	}
+	data.MatchType()
+	return &data, time.Unix(0, cacheFile.SavedOn*int64(time.Millisecond)), nil
+}
+
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
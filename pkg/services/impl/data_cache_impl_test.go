@@ -0,0 +1,138 @@
+package impl_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/flagsense/go-sdk/constants"
+	"github.com/flagsense/go-sdk/pkg/dto"
+	"github.com/flagsense/go-sdk/pkg/enums"
+	"github.com/flagsense/go-sdk/pkg/model"
+	"github.com/flagsense/go-sdk/pkg/services/impl"
+)
+
+func TestDataCacheLoad(t *testing.T) {
+	sdkConfig := &model.SDKConfig{SDKId: "sdk", SDKSecret: "secret", Environment: "DEV"}
+	data := &dto.Data{
+		Flags:         map[string]dto.FlagDTO{"flag": {ID: "flag", Type: dto.BOOL}},
+		Segments:      map[string]dto.SegmentDTO{},
+		LastUpdatedOn: 42,
+	}
+
+	tests := []struct {
+		name   string
+		modify func(cacheFile map[string]json.RawMessage)
+		valid  bool
+	}{
+		{"saved data", func(cacheFile map[string]json.RawMessage) {}, true},
+		{"checksum mismatch", func(cacheFile map[string]json.RawMessage) {
+			cacheFile["data"] = json.RawMessage(`{"flags":{},"segments":{},"lastUpdatedOn":43}`)
+		}, false},
+		{"other version", func(cacheFile map[string]json.RawMessage) {
+			cacheFile["version"], _ = json.Marshal(impl.DATA_CACHE_VERSION + 1)
+		}, false},
+		{"other sdk", func(cacheFile map[string]json.RawMessage) { cacheFile["sdkId"] = json.RawMessage(`"other"`) }, false},
+		{"other environment", func(cacheFile map[string]json.RawMessage) { cacheFile["environment"] = json.RawMessage(`"PROD"`) }, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cache := impl.NewDataCache(filepath.Join(t.TempDir(), "flags.json"), sdkConfig)
+			if err := cache.Save(data); err != nil {
+				t.Fatal(err)
+			}
+			content, err := ioutil.ReadFile(cache.FilePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var cacheFile map[string]json.RawMessage
+			if err := json.Unmarshal(content, &cacheFile); err != nil {
+				t.Fatal(err)
+			}
+			test.modify(cacheFile)
+			content, _ = json.Marshal(cacheFile)
+			if err := ioutil.WriteFile(cache.FilePath, content, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			loaded, savedOn, err := cache.Load()
+			if !test.valid {
+				if err == nil {
+					t.Fatal("expected the cache to be rejected")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if loaded.LastUpdatedOn != 42 || loaded.Flags["flag"].Type != dto.BOOL || savedOn.IsZero() {
+				t.Fatalf("unexpected cache content %+v saved on %v", loaded, savedOn)
+			}
+		})
+	}
+}
+
+func TestDataCacheLoadBrokenFile(t *testing.T) {
+	sdkConfig := &model.SDKConfig{SDKId: "sdk", SDKSecret: "secret", Environment: "DEV"}
+	for name, content := range map[string]string{
+		"missing":  "",
+		"not json": "{flags",
+	} {
+		t.Run(name, func(t *testing.T) {
+			cache := impl.NewDataCache(filepath.Join(t.TempDir(), "flags.json"), sdkConfig)
+			if content != "" {
+				if err := ioutil.WriteFile(cache.FilePath, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if _, _, err := cache.Load(); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestCachedDataIsServedStaleWhileTheServerIsDown(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	cacheFile := filepath.Join(t.TempDir(), "flags.json")
+	cache := impl.NewDataCache(cacheFile, &model.SDKConfig{SDKId: "cached", SDKSecret: "secret", Environment: constants.DEV})
+	err := cache.Save(&dto.Data{
+		Flags: map[string]dto.FlagDTO{"flag": {
+			ID:            "flag",
+			Type:          dto.BOOL,
+			Variants:      map[string]dto.Variant{"on": {Value: true, Name: "on"}},
+			VariantsOrder: []string{"on"},
+			EnvData:       dto.EnvData{Traffic: map[string]int{"on": 100000}, Status: dto.ACTIVE},
+		}},
+		Segments:      map[string]dto.SegmentDTO{},
+		LastUpdatedOn: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	captureEvents := false
+	service := impl.NewFlagsenseServiceWithOptions("cached", "secret", enums.NewEnvironment(constants.DEV), impl.Options{
+		SDKServiceUrl: server.URL,
+		CacheFile:     cacheFile,
+		CaptureEvents: &captureEvents,
+	})
+	defer service.Close()
+
+	if !service.InitializationComplete() {
+		t.Fatal("expected the cached data to initialize the service")
+	}
+	if status := service.DataSourceStatus(); !status.Stale {
+		t.Fatalf("expected a stale status, got %+v", status)
+	}
+	if value := service.BooleanVariation(model.FSFlag{FlagId: "flag", DefaultValue: false}, model.FSUser{UserId: "user"}).Value; value != true {
+		t.Fatalf("expected the cached variant, got %v", value)
+	}
+}
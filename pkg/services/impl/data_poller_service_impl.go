@@ -4,14 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"github.com/flagsense/go-sdk/config"
 	"github.com/flagsense/go-sdk/pkg/dto"
 	flagsenseHttpClient "github.com/flagsense/go-sdk/pkg/infrastructure/http"
 	"github.com/flagsense/go-sdk/pkg/model"
-	"fmt"
+	"github.com/flagsense/go-sdk/pkg/services"
 	"github.com/teltech/logger"
 	"net/http"
-	"sync"
 	"time"
 )
 
@@ -30,9 +30,8 @@ type DataPollerServiceImpl struct {
 	logger          *logger.Log
 	config          *config.Store
 	client          *http.Client
-	Data            *dto.Data
-	Cond            *sync.Cond
-	Mutex           *sync.Mutex
+	Cache           *DataCacheImpl
+	services.DataSourceUpdater
 }
 
 type DataPollerRequest struct {
@@ -41,38 +40,49 @@ type DataPollerRequest struct {
 }
 
 func NewDataPollerService(sdkConfig *model.SDKConfig, pollingInterval time.Duration, logger *logger.Log, config *config.Store,
-	client *http.Client, data *dto.Data) *DataPollerServiceImpl {
-	mutex := &sync.Mutex{}
-	cond := sync.NewCond(mutex)
+	client *http.Client, updater services.DataSourceUpdater) *DataPollerServiceImpl {
 	return &DataPollerServiceImpl{
-		SDKConfig:       sdkConfig,
-		PollingInterval: pollingInterval,
-		logger:          logger,
-		config:          config,
-		client:          client,
-		Data:            data,
-		Cond:            cond,
-		Mutex:           mutex,
+		SDKConfig:         sdkConfig,
+		PollingInterval:   pollingInterval,
+		logger:            logger,
+		config:            config,
+		client:            client,
+		DataSourceUpdater: updater,
 	}
 }
 
 func (dps *DataPollerServiceImpl) Start(ctx context.Context) {
-	// for first time initialization at start
-	dps.fetchLatest(ctx)
-
-	tick := time.NewTicker(dps.PollingInterval)
+	// the first fetch starts right away
+	var delay time.Duration
+	failures := 0
 	for {
+		timer := time.NewTimer(delay)
 		select {
-		case <-tick.C:
-			dps.fetchLatest(ctx)
+		case <-timer.C:
 		case <-ctx.Done():
+			timer.Stop()
 			//dps.logger.Info("Polling Config Manager Stopped")
 			return
 		}
+
+		err := dps.fetchLatest(ctx)
+		if isUnrecoverable(err) {
+			dps.logger.Errorf("stopped polling for flags data, error:%+v", err)
+			return
+		}
+		if err != nil {
+			failures++
+		} else {
+			failures = 0
+		}
+		delay = dps.PollingInterval
+		if retryDelay := retriableDelay(err, failures, dps.PollingInterval); retryDelay > 0 {
+			delay = retryDelay
+		}
 	}
 }
 
-func (dps *DataPollerServiceImpl) fetchLatest(ctx context.Context) {
+func (dps *DataPollerServiceImpl) fetchLatest(ctx context.Context) error {
 	//fmt.Println("Fetching latest data at: ", time.Now().String())
 	endpoint := fmt.Sprintf("%s/fetchLatest", dps.config.Services.SDKService.HttpEndpoint.Url)
 	headers := map[string]string{
@@ -83,50 +93,80 @@ func (dps *DataPollerServiceImpl) fetchLatest(ctx context.Context) {
 	}
 
 	payload := DataPollerRequest{
-		LastUpdatedOn: dps.Data.LastUpdatedOn,
+		LastUpdatedOn: dps.Current().LastUpdatedOn,
 		Environment:   dps.SDKConfig.Environment,
 	}
 	requestBody, err := json.Marshal(payload)
 	if err != nil {
 		//dps.logger.Errorf("error while parsing request payload:%+v, error:%+v", payload, err)
-		return
+		dps.SetError(err)
+		return err
 	}
 	response, err := flagsenseHttpClient.MakeHttpRequest(ctx, "POST", endpoint, dps.client,
 		bytes.NewBuffer(requestBody), headers)
 	if err != nil {
 		//dps.logger.Errorf("error while fetching latest flags data:%+v, error:%+v", payload, err)
-		return
+		dps.SetError(err)
+		return err
 	}
 	if response == nil || len(response) == 0 {
-		return
+		return dps.updateData(dto.Data{})
 	}
 	var newData dto.Data
 	err = json.Unmarshal(response, &newData)
 	if err != nil {
 		//dps.logger.Errorf("error while parsing response payload, error:%+v", err)
-		return
+		dps.SetError(err)
+		return err
 	}
 
-	if newData.LastUpdatedOn > 0 && newData.Flags != nil && newData.Segments != nil {
-		newData.MatchType()
-		if len(newData.Segments) > 0 {
-			dps.Data.Segments = newData.Segments
+	return dps.updateData(newData)
+}
+
+func (dps *DataPollerServiceImpl) updateData(newData dto.Data) error {
+	// a response without data means that nothing changed since lastUpdatedOn
+	if newData.LastUpdatedOn <= 0 || newData.Flags == nil || newData.Segments == nil {
+		return dps.Update(func(current *dto.Data) (*dto.Data, error) {
+			return nil, nil
+		})
+	}
+	newData.MatchType()
+	err := dps.Update(func(current *dto.Data) (*dto.Data, error) {
+		// the store starts without maps, which must not replace the empty maps of the first response
+		if len(newData.Segments) == 0 && current.Segments != nil {
+			newData.Segments = current.Segments
 		}
-		if len(newData.Flags) > 0 {
-			dps.Data.Flags = newData.Flags
+		if len(newData.Flags) == 0 && current.Flags != nil {
+			newData.Flags = current.Flags
 		}
+		return &newData, nil
+	})
+	if err == nil {
+		dps.saveCache()
+	}
+	return err
+}
 
-		dps.Mutex.Lock()
-		dps.Data.LastUpdatedOn = newData.LastUpdatedOn
-		dps.Mutex.Unlock()
-		dps.Cond.Broadcast()
+// LoadCache serves the cached data until the first fetch, a missing or broken cache file is ignored
+func (dps *DataPollerServiceImpl) LoadCache() {
+	if dps.Cache == nil {
+		return
+	}
+	data, savedOn, err := dps.Cache.Load()
+	if err != nil {
+		dps.logger.Errorf("error while loading flags cache:%s, error:%+v", dps.Cache.FilePath, err)
+		return
+	}
+	if err := dps.LoadCached(data, savedOn); err != nil {
+		dps.logger.Errorf("error while loading cached flags data:%s, error:%+v", dps.Cache.FilePath, err)
 	}
 }
 
-func (dps *DataPollerServiceImpl) WaitForInitializationComplete() {
-	dps.Mutex.Lock()
-	defer dps.Mutex.Unlock()
-	for dps.Data.LastUpdatedOn == ZER0 {
-		dps.Cond.Wait()
+func (dps *DataPollerServiceImpl) saveCache() {
+	if dps.Cache == nil {
+		return
+	}
+	if err := dps.Cache.Save(dps.Current()); err != nil {
+		dps.logger.Errorf("error while saving flags cache:%s, error:%+v", dps.Cache.FilePath, err)
 	}
 }
@@ -0,0 +1,55 @@
+package impl_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/flagsense/go-sdk/constants"
+	"github.com/flagsense/go-sdk/pkg/enums"
+	"github.com/flagsense/go-sdk/pkg/services/impl"
+)
+
+func TestPollerBacksOffFromThePollingInterval(t *testing.T) {
+	for name, status := range map[string]int{
+		"too many requests": http.StatusTooManyRequests,
+		"server error":      http.StatusServiceUnavailable,
+	} {
+		t.Run(name, func(t *testing.T) {
+			var lock sync.Mutex
+			var requests []time.Time
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				lock.Lock()
+				requests = append(requests, time.Now())
+				lock.Unlock()
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			pollingInterval := 50 * time.Millisecond
+			captureEvents := false
+			service := impl.NewFlagsenseServiceWithOptions("poller", "secret", enums.NewEnvironment(constants.DEV), impl.Options{
+				SDKServiceUrl:   server.URL,
+				PollingInterval: pollingInterval,
+				CaptureEvents:   &captureEvents,
+			})
+			time.Sleep(8 * pollingInterval)
+			service.Close()
+
+			lock.Lock()
+			defer lock.Unlock()
+			// retries after 1, 2 and 4 intervals fit, an earlier retry would add more requests
+			if len(requests) < 2 || len(requests) > 4 {
+				t.Fatalf("expected 2 to 4 requests, got %d", len(requests))
+			}
+			for i := 1; i < len(requests); i++ {
+				expected := pollingInterval << (i - 1)
+				if gap := requests[i].Sub(requests[i-1]); gap < expected {
+					t.Fatalf("retry %d after %v, expected at least %v", i, gap, expected)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/flagsense/go-sdk/pkg/dto"
+	"github.com/flagsense/go-sdk/pkg/infrastructure/http"
+	"github.com/flagsense/go-sdk/pkg/model"
+	"github.com/flagsense/go-sdk/pkg/services"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// DataUpdater is the single write path into the feature store shared by all data sources. It also tracks the
+// data source status, which it publishes to status listeners.
+type DataUpdater struct {
+	Store           services.FeatureStore
+	Cond            *sync.Cond
+	Mutex           *sync.Mutex
+	lastError       error
+	status          model.FSDataSourceStatus
+	statusListeners []chan model.FSDataSourceStatus
+}
+
+func NewDataUpdater(store services.FeatureStore) *DataUpdater {
+	mutex := &sync.Mutex{}
+	return &DataUpdater{
+		Store: store,
+		Cond:  sync.NewCond(mutex),
+		Mutex: mutex,
+		status: model.FSDataSourceStatus{
+			State:      model.DATA_SOURCE_INITIALIZING,
+			StateSince: time.Now(),
+		},
+	}
+}
+
+func (du *DataUpdater) Current() *dto.Data {
+	return du.Store.All()
+}
+
+// Update serializes writers, the snapshot passed to modify must be copied and not changed in place.
+// modify returns nil when the data source confirmed that nothing changed.
+func (du *DataUpdater) Update(modify func(current *dto.Data) (*dto.Data, error)) error {
+	du.Mutex.Lock()
+	if du.status.State == model.DATA_SOURCE_OFF {
+		du.Mutex.Unlock()
+		return nil
+	}
+	newData, err := modify(du.Store.All())
+	if err != nil {
+		du.Mutex.Unlock()
+		return err
+	}
+	// no new data still confirms that the current data is up to date
+	if newData != nil {
+		if err := du.Store.Init(newData); err != nil {
+			du.setError(err)
+			du.Mutex.Unlock()
+			return err
+		}
+	}
+	du.lastError = nil
+	du.status.LastSuccessfulFetch = time.Now()
+	du.status.ConsecutiveFailures = 0
+	wasStale := du.status.Stale
+	du.status.Stale = false
+	if du.Store.IsInitialized() && du.status.State != model.DATA_SOURCE_VALID {
+		du.setState(model.DATA_SOURCE_VALID)
+	} else if wasStale {
+		du.publishStatus()
+	}
+	du.Mutex.Unlock()
+	du.Cond.Broadcast()
+	return nil
+}
+
+// LoadCached serves data from the local cache until the first fetch, it is ignored once any data was loaded.
+// The status stays stale until an update confirms the data.
+func (du *DataUpdater) LoadCached(data *dto.Data, savedOn time.Time) error {
+	du.Mutex.Lock()
+	defer du.Mutex.Unlock()
+	if data == nil || du.status.State == model.DATA_SOURCE_OFF || du.Store.IsInitialized() {
+		return nil
+	}
+	if err := du.Store.Init(data); err != nil {
+		return err
+	}
+	du.status.Stale = true
+	du.status.LastSuccessfulFetch = savedOn
+	du.setState(model.DATA_SOURCE_VALID)
+	du.Cond.Broadcast()
+	return nil
+}
+
+// SetError records a failed attempt to load the data. Unrecoverable errors move the data source into the FAILED state
+// and wake up everyone waiting for initialization.
+func (du *DataUpdater) SetError(err error) {
+	du.Mutex.Lock()
+	defer du.Mutex.Unlock()
+	du.setError(err)
+}
+
+func (du *DataUpdater) setError(err error) {
+	if err == nil || du.status.State == model.DATA_SOURCE_OFF {
+		return
+	}
+	du.lastError = err
+	du.status.LastError = err.Error()
+	du.status.LastErrorKind = errorKind(err)
+	du.status.StatusCode = 0
+	var statusError *httptrp.HttpStatusError
+	if errors.As(err, &statusError) {
+		du.status.StatusCode = statusError.StatusCode
+	}
+	du.status.ConsecutiveFailures++
+
+	switch {
+	case isUnrecoverable(err):
+		du.setState(model.DATA_SOURCE_FAILED)
+		du.Cond.Broadcast()
+	case du.status.State == model.DATA_SOURCE_VALID:
+		du.setState(model.DATA_SOURCE_INTERRUPTED)
+	default:
+		du.publishStatus()
+	}
+}
+
+// Close moves the data source into the OFF state, later updates are ignored
+func (du *DataUpdater) Close() {
+	du.Mutex.Lock()
+	defer du.Mutex.Unlock()
+	du.setState(model.DATA_SOURCE_OFF)
+	du.Cond.Broadcast()
+}
+
+func (du *DataUpdater) GetStatus() model.FSDataSourceStatus {
+	du.Mutex.Lock()
+	defer du.Mutex.Unlock()
+	return du.status
+}
+
+// AddStatusListener returns a channel receiving every status change. A listener which falls behind misses
+// intermediate statuses, GetStatus always returns the latest one.
+func (du *DataUpdater) AddStatusListener() <-chan model.FSDataSourceStatus {
+	du.Mutex.Lock()
+	defer du.Mutex.Unlock()
+	listener := make(chan model.FSDataSourceStatus, LISTENER_BUFFER_SIZE)
+	du.statusListeners = append(du.statusListeners, listener)
+	return listener
+}
+
+func (du *DataUpdater) RemoveStatusListener(listener <-chan model.FSDataSourceStatus) {
+	du.Mutex.Lock()
+	defer du.Mutex.Unlock()
+	for i, statusListener := range du.statusListeners {
+		if statusListener == listener {
+			du.statusListeners = append(du.statusListeners[:i], du.statusListeners[i+1:]...)
+			close(statusListener)
+			return
+		}
+	}
+}
+
+func (du *DataUpdater) InitializationError() error {
+	du.Mutex.Lock()
+	defer du.Mutex.Unlock()
+	if du.Store.IsInitialized() {
+		return nil
+	}
+	return du.lastError
+}
+
+// WaitForInitializationComplete also returns once the data source has failed, as the data will never arrive then
+func (du *DataUpdater) WaitForInitializationComplete() {
+	du.Mutex.Lock()
+	defer du.Mutex.Unlock()
+	for !du.Store.IsInitialized() && !du.stopped() {
+		du.Cond.Wait()
+	}
+}
+
+func (du *DataUpdater) WaitForInitialization(ctx context.Context) error {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			// taking the lock makes sure the waiter is either before its check or already waiting
+			du.Mutex.Lock()
+			du.Cond.Broadcast()
+			du.Mutex.Unlock()
+		case <-done:
+		}
+	}()
+
+	du.Mutex.Lock()
+	defer du.Mutex.Unlock()
+	for !du.Store.IsInitialized() {
+		if du.stopped() {
+			return &model.FSInitializationError{
+				Kind:      errorKind(du.lastError),
+				LastError: du.lastError,
+				Err:       model.ErrDataSourceFailed,
+			}
+		}
+		if ctx.Err() != nil {
+			return &model.FSInitializationError{
+				Kind:      errorKind(du.lastError),
+				LastError: du.lastError,
+				Err:       ctx.Err(),
+			}
+		}
+		du.Cond.Wait()
+	}
+	return nil
+}
+
+func (du *DataUpdater) stopped() bool {
+	return du.status.State == model.DATA_SOURCE_FAILED || du.status.State == model.DATA_SOURCE_OFF
+}
+
+func (du *DataUpdater) setState(state string) {
+	if du.status.State == state {
+		return
+	}
+	du.status.State = state
+	du.status.StateSince = time.Now()
+	du.publishStatus()
+}
+
+func (du *DataUpdater) publishStatus() {
+	for _, listener := range du.statusListeners {
+		select {
+		case listener <- du.status:
+		default:
+		}
+	}
+}
+
+func errorKind(err error) string {
+	if err == nil {
+		return ""
+	}
+	var statusError *httptrp.HttpStatusError
+	if errors.As(err, &statusError) {
+		if statusError.IsUnrecoverable() {
+			return model.INIT_FAILURE_BAD_CREDENTIALS
+		}
+		return model.INIT_FAILURE_HTTP_ERROR
+	}
+	var syntaxError *json.SyntaxError
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &syntaxError) || errors.As(err, &typeError) {
+		return model.INIT_FAILURE_INVALID_DATA
+	}
+	// file errors look like network errors, they carry a Timeout method as well
+	var pathError *os.PathError
+	if errors.As(err, &pathError) {
+		return model.INIT_FAILURE_UNKNOWN
+	}
+	var networkError net.Error
+	if errors.As(err, &networkError) {
+		return model.INIT_FAILURE_NETWORK
+	}
+	return model.INIT_FAILURE_UNKNOWN
+}
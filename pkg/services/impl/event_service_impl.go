@@ -32,6 +32,7 @@ type EventServiceImpl struct {
 	refreshLock    *sync.Mutex
 	config         *config.Store
 	machineId      string
+	flushInterval  time.Duration
 }
 
 type VariantsRequest struct {
@@ -47,25 +48,23 @@ type VariantsRequest struct {
 const (
 	EVENT_FLUSH_INTITAL_DELAY = 2
 	EVENT_FLUSH_INTERVAL      = 5
+	MIN_EVENT_FLUSH_INTERVAL  = time.Second
 	SDK_TYPE                  = "go"
 )
 
-var MILLIS_IN_EVENT_FLUSH_INTERVAL int64 = EVENT_FLUSH_INTERVAL * 60 * 1000
-
-func NewEventService(logger *logger.Log, sdkConfig *model.SDKConfig, client *http.Client, config *config.Store) *EventServiceImpl {
+func NewEventService(logger *logger.Log, sdkConfig *model.SDKConfig, client *http.Client, config *config.Store,
+	flushInterval time.Duration) *EventServiceImpl {
 	errors := cmap.New()
 	data := cmap.New()
 	codeBugs := cmap.New()
-	timeslot := getTimeSlot(time.Now().Unix() * 1000)
 	requests := cmap.New()
-	return &EventServiceImpl{
+	es := &EventServiceImpl{
 		logger:         logger,
 		sdkConfig:      sdkConfig,
 		requests:       &requests,
 		errors:         &errors,
 		data:           &data,
 		codeBugs:		&codeBugs,
-		timeslot:       timeslot,
 		variantMapLock: &sync.Mutex{},
 		codeBugsLock:   &sync.Mutex{},
 		errorLock:      &sync.Mutex{},
@@ -73,13 +72,20 @@ func NewEventService(logger *logger.Log, sdkConfig *model.SDKConfig, client *htt
 		client:         client,
 		config:         config,
 		machineId:      guuid.NewString(),
+		flushInterval:  flushInterval,
 	}
+	es.timeslot = es.getTimeSlot(time.Now().Unix() * 1000)
+	return es
 }
 
 func (es *EventServiceImpl) Start(ctx context.Context) {
-	time.Sleep(EVENT_FLUSH_INTITAL_DELAY * time.Minute)
+	initialDelay := EVENT_FLUSH_INTITAL_DELAY * time.Minute
+	if es.flushInterval < initialDelay {
+		initialDelay = es.flushInterval
+	}
+	time.Sleep(initialDelay)
 	es.Run(ctx)
-	tick := time.NewTicker(EVENT_FLUSH_INTERVAL * time.Minute)
+	tick := time.NewTicker(es.flushInterval)
 	for {
 		select {
 		case <-tick.C:
@@ -98,7 +104,7 @@ func (es *EventServiceImpl) AddEvaluationCount(flagId string, variantKey string)
 	if !es.config.Constants.CaptureEvents {
 		return
 	}
-	currentTimeSlot := getTimeSlot(time.Now().Unix() * 1000)
+	currentTimeSlot := es.getTimeSlot(time.Now().Unix() * 1000)
 	if currentTimeSlot != es.timeslot {
 		es.checkAndRefreshData(currentTimeSlot)
 	}
@@ -127,7 +133,7 @@ func (es *EventServiceImpl) AddErrorsCount(flagId string) {
 	if !es.config.Constants.CaptureEvents {
 		return
 	}
-	currentTimeSlot := getTimeSlot(time.Now().Unix() * 1000)
+	currentTimeSlot := es.getTimeSlot(time.Now().Unix() * 1000)
 	if currentTimeSlot != es.timeslot {
 		es.checkAndRefreshData(currentTimeSlot)
 	}
@@ -149,7 +155,7 @@ func (es *EventServiceImpl) AddCodeBugsCount(flagId string, variantKey string)
 	if !es.config.Constants.CaptureEvents {
 		return
 	}
-	currentTimeSlot := getTimeSlot(time.Now().Unix() * 1000)
+	currentTimeSlot := es.getTimeSlot(time.Now().Unix() * 1000)
 	if currentTimeSlot != es.timeslot {
 		es.checkAndRefreshData(currentTimeSlot)
 	}
@@ -229,7 +235,7 @@ func (es *EventServiceImpl) SendEvents(ctx context.Context, requestBody Variants
 		HEADER_SDK_SECRET: es.sdkConfig.SDKSecret,
 	}
 
-	es.checkAndRefreshData(getTimeSlot(time.Now().Unix() * 1000))
+	es.checkAndRefreshData(es.getTimeSlot(time.Now().Unix() * 1000))
 
 	body, err := json.Marshal(requestBody)
 	if err != nil {
@@ -248,10 +254,11 @@ func (es *EventServiceImpl) ShutdownHook(ctx context.Context) {
 	if !es.config.Constants.CaptureEvents {
 		return
 	}
-	es.refreshData(getTimeSlot(time.Now().Unix() * 1000))
+	es.refreshData(es.getTimeSlot(time.Now().Unix() * 1000))
 	es.Run(ctx)
 }
 
-func getTimeSlot(time int64) int64 {
-	return (time / MILLIS_IN_EVENT_FLUSH_INTERVAL) * MILLIS_IN_EVENT_FLUSH_INTERVAL
+func (es *EventServiceImpl) getTimeSlot(time int64) int64 {
+	millisInFlushInterval := es.flushInterval.Milliseconds()
+	return (time / millisInFlushInterval) * millisInFlushInterval
 }
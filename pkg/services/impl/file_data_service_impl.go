@@ -0,0 +1,112 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/flagsense/go-sdk/pkg/dto"
+	"github.com/flagsense/go-sdk/pkg/services"
+	"github.com/teltech/logger"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+const (
+	FILE_WATCH_INTERVAL = 1 * time.Second
+)
+
+type FileDataServiceImpl struct {
+	FilePath      string
+	Watch         bool
+	WatchInterval time.Duration
+	logger        *logger.Log
+	modTime       time.Time
+	size          int64
+	services.DataSourceUpdater
+}
+
+func NewFileDataService(filePath string, watch bool, watchInterval time.Duration, logger *logger.Log,
+	updater services.DataSourceUpdater) *FileDataServiceImpl {
+	if watchInterval <= 0 {
+		watchInterval = FILE_WATCH_INTERVAL
+	}
+	return &FileDataServiceImpl{
+		FilePath:          filePath,
+		Watch:             watch,
+		WatchInterval:     watchInterval,
+		logger:            logger,
+		DataSourceUpdater: updater,
+	}
+}
+
+func (fds *FileDataServiceImpl) Start(ctx context.Context) {
+	if !fds.Watch {
+		// nothing loads the file again, a failure fails the data source instead of leaving it initializing
+		if err := fds.reload(); err != nil {
+			fds.SetError(&unrecoverableError{err: err})
+		}
+		return
+	}
+	fds.reloadIfChanged()
+
+	tick := time.NewTicker(fds.WatchInterval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-tick.C:
+			fds.reloadIfChanged()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (fds *FileDataServiceImpl) reloadIfChanged() {
+	if err := fds.reload(); err != nil {
+		fds.SetError(err)
+	}
+}
+
+func (fds *FileDataServiceImpl) reload() error {
+	info, err := os.Stat(fds.FilePath)
+	if err != nil {
+		fds.logger.Errorf("error while reading flags data file:%s, error:%+v", fds.FilePath, err)
+		return err
+	}
+	if info.ModTime().Equal(fds.modTime) && info.Size() == fds.size {
+		return nil
+	}
+	if err := fds.load(info); err != nil {
+		fds.logger.Errorf("error while loading flags data file:%s, error:%+v", fds.FilePath, err)
+		return err
+	}
+	fds.modTime = info.ModTime()
+	fds.size = info.Size()
+	return nil
+}
+
+func (fds *FileDataServiceImpl) load(info os.FileInfo) error {
+	content, err := ioutil.ReadFile(fds.FilePath)
+	if err != nil {
+		return err
+	}
+	var newData dto.Data
+	if err := json.Unmarshal(content, &newData); err != nil {
+		return err
+	}
+	if newData.Flags == nil {
+		newData.Flags = map[string]dto.FlagDTO{}
+	}
+	if newData.Segments == nil {
+		newData.Segments = map[string]dto.SegmentDTO{}
+	}
+	// hand written files usually skip lastUpdatedOn, the modification time still marks the data as loaded
+	if newData.LastUpdatedOn <= 0 {
+		newData.LastUpdatedOn = float64(info.ModTime().UnixNano() / int64(time.Millisecond))
+	}
+	newData.MatchType()
+
+	return fds.Update(func(current *dto.Data) (*dto.Data, error) {
+		return &newData, nil
+	})
+}
@@ -0,0 +1,44 @@
+package impl_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flagsense/go-sdk/constants"
+	"github.com/flagsense/go-sdk/pkg/enums"
+	"github.com/flagsense/go-sdk/pkg/model"
+	"github.com/flagsense/go-sdk/pkg/services/impl"
+)
+
+func TestUnwatchedDataFileFailureEndsInitialization(t *testing.T) {
+	invalidFile := filepath.Join(t.TempDir(), "invalid.json")
+	if err := os.WriteFile(invalidFile, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for name, path := range map[string]string{
+		"missing": filepath.Join(t.TempDir(), "missing.json"),
+		"invalid": invalidFile,
+	} {
+		t.Run(name, func(t *testing.T) {
+			service := impl.NewFlagsenseServiceWithOptions("file", "secret", enums.NewEnvironment(constants.DEV), impl.Options{
+				DataFile: path,
+			})
+			defer service.Close()
+
+			initialized := make(chan struct{})
+			go func() {
+				service.WaitForInitializationComplete()
+				close(initialized)
+			}()
+			waitFor(t, "initialization", initialized)
+
+			if status := service.DataSourceStatus(); status.State != model.DATA_SOURCE_FAILED {
+				t.Fatalf("expected FAILED, got %s", status.State)
+			}
+			if service.InitializationError() == nil {
+				t.Fatal("expected an initialization error")
+			}
+		})
+	}
+}
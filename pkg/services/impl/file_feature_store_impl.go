@@ -0,0 +1,92 @@
+package impl
+
+import (
+	"github.com/flagsense/go-sdk/pkg/dto"
+	"os"
+	"sync"
+	"time"
+)
+
+// FileFeatureStoreImpl keeps the flags data in a file shared by several processes. One process, usually a relay,
+// writes it while the others run in daemon mode and only pick up its changes through Refresh. Reads are served
+// from memory.
+type FileFeatureStoreImpl struct {
+	FilePath string
+	memory   *InMemoryFeatureStoreImpl
+	cache    *DataCacheImpl
+	lock     *sync.Mutex
+	modTime  time.Time
+	size     int64
+}
+
+// NewFileFeatureStore loads the file when it exists already, a missing file leaves the store uninitialized
+func NewFileFeatureStore(filePath string) *FileFeatureStoreImpl {
+	fs := &FileFeatureStoreImpl{
+		FilePath: filePath,
+		memory:   NewInMemoryFeatureStore(),
+		cache:    NewDataCache(filePath, nil),
+		lock:     &sync.Mutex{},
+	}
+	fs.Refresh()
+	return fs
+}
+
+// Init writes and syncs the file before it returns. Data sources call it holding the DataUpdater lock, so a slow disk
+// delays the next update and DataSourceStatus calls, evaluations keep reading from memory.
+func (fs *FileFeatureStoreImpl) Init(data *dto.Data) error {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+	if err := fs.cache.Save(data); err != nil {
+		return err
+	}
+	// our own write must not be read back as a change
+	if info, err := os.Stat(fs.FilePath); err == nil {
+		fs.modTime = info.ModTime()
+		fs.size = info.Size()
+	}
+	return fs.memory.Init(data)
+}
+
+func (fs *FileFeatureStoreImpl) GetFlag(flagId string) (dto.FlagDTO, bool) {
+	return fs.memory.GetFlag(flagId)
+}
+
+func (fs *FileFeatureStoreImpl) GetSegment(segmentId string) (dto.SegmentDTO, bool) {
+	return fs.memory.GetSegment(segmentId)
+}
+
+func (fs *FileFeatureStoreImpl) All() *dto.Data {
+	return fs.memory.All()
+}
+
+func (fs *FileFeatureStoreImpl) Upsert(data *dto.Data) error {
+	return fs.Init(mergeData(fs.All(), data))
+}
+
+func (fs *FileFeatureStoreImpl) IsInitialized() bool {
+	return fs.memory.IsInitialized()
+}
+
+func (fs *FileFeatureStoreImpl) SetUpdateListener(listener func(previous *dto.Data, current *dto.Data)) {
+	fs.memory.SetUpdateListener(listener)
+}
+
+// Refresh reloads the file when another process changed it, a broken file keeps the last loaded data
+func (fs *FileFeatureStoreImpl) Refresh() error {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+	info, err := os.Stat(fs.FilePath)
+	if err != nil {
+		return err
+	}
+	if info.ModTime().Equal(fs.modTime) && info.Size() == fs.size {
+		return nil
+	}
+	data, _, err := fs.cache.Load()
+	if err != nil {
+		return err
+	}
+	fs.modTime = info.ModTime()
+	fs.size = info.Size()
+	return fs.memory.Init(data)
+}
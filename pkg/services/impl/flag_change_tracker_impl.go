@@ -0,0 +1,284 @@
+package impl
+
+import (
+	"context"
+	"github.com/flagsense/go-sdk/pkg/dto"
+	"github.com/flagsense/go-sdk/pkg/model"
+	"github.com/flagsense/go-sdk/pkg/services"
+	"reflect"
+	"sync"
+)
+
+const (
+	LISTENER_BUFFER_SIZE = 100
+)
+
+// FlagChangeTrackerImpl diffs every new flags snapshot against the previous one and dispatches the changes
+// on its own goroutine, so slow listeners never block the data source. Updates arriving while a dispatch
+// is running are coalesced into one, and a listener whose buffer is full misses the events that do not fit.
+type FlagChangeTrackerImpl struct {
+	Store              services.FeatureStore
+	UserVariantService services.UserVariantService
+	updated            chan struct{}
+	pending            *dataUpdate
+	pendingLock        *sync.Mutex
+	lock               *sync.Mutex
+	flagListeners      []*flagListener
+	valueListeners     []*flagValueListener
+}
+
+type dataUpdate struct {
+	previous *dto.Data
+	current  *dto.Data
+}
+
+// flagListener is closed under its own lock, so sends never race with RemoveFlagChangeListener
+type flagListener struct {
+	lock    sync.Mutex
+	closed  bool
+	channel chan model.FSFlagChangeEvent
+}
+
+type flagValueListener struct {
+	flagId    string
+	user      model.FSUser
+	lastValue model.FSVariation
+	lock      sync.Mutex
+	closed    bool
+	channel   chan model.FSFlagValueChangeEvent
+}
+
+func NewFlagChangeTracker(store services.FeatureStore, userVariantService services.UserVariantService) *FlagChangeTrackerImpl {
+	return &FlagChangeTrackerImpl{
+		Store:              store,
+		UserVariantService: userVariantService,
+		updated:            make(chan struct{}, 1),
+		pendingLock:        &sync.Mutex{},
+		lock:               &sync.Mutex{},
+	}
+}
+
+func (fct *FlagChangeTrackerImpl) Start(ctx context.Context) {
+	for {
+		select {
+		case <-fct.updated:
+			fct.pendingLock.Lock()
+			update := fct.pending
+			fct.pending = nil
+			fct.pendingLock.Unlock()
+			if update != nil {
+				fct.dispatch(changedFlags(update.previous, update.current))
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// DataUpdated never blocks, it is called by the data source while the updater is locked
+func (fct *FlagChangeTrackerImpl) DataUpdated(previous *dto.Data, current *dto.Data) {
+	fct.pendingLock.Lock()
+	if fct.pending == nil {
+		fct.pending = &dataUpdate{previous: previous}
+	}
+	fct.pending.current = current
+	fct.pendingLock.Unlock()
+
+	select {
+	case fct.updated <- struct{}{}:
+	default:
+	}
+}
+
+func (fct *FlagChangeTrackerImpl) AddFlagChangeListener() <-chan model.FSFlagChangeEvent {
+	fct.lock.Lock()
+	defer fct.lock.Unlock()
+	listener := &flagListener{
+		channel: make(chan model.FSFlagChangeEvent, LISTENER_BUFFER_SIZE),
+	}
+	fct.flagListeners = append(fct.flagListeners, listener)
+	return listener.channel
+}
+
+func (fct *FlagChangeTrackerImpl) RemoveFlagChangeListener(listener <-chan model.FSFlagChangeEvent) {
+	fct.lock.Lock()
+	var removed *flagListener
+	for i, flagListener := range fct.flagListeners {
+		if flagListener.channel == listener {
+			fct.flagListeners = append(fct.flagListeners[:i:i], fct.flagListeners[i+1:]...)
+			removed = flagListener
+			break
+		}
+	}
+	fct.lock.Unlock()
+
+	if removed != nil {
+		removed.lock.Lock()
+		removed.closed = true
+		close(removed.channel)
+		removed.lock.Unlock()
+	}
+}
+
+func (fct *FlagChangeTrackerImpl) AddFlagValueChangeListener(flagId string, user model.FSUser) <-chan model.FSFlagValueChangeEvent {
+	listener := &flagValueListener{
+		flagId:    flagId,
+		user:      user,
+		lastValue: fct.currentVariation(flagId, user),
+		channel:   make(chan model.FSFlagValueChangeEvent, LISTENER_BUFFER_SIZE),
+	}
+	fct.lock.Lock()
+	defer fct.lock.Unlock()
+	fct.valueListeners = append(fct.valueListeners, listener)
+	return listener.channel
+}
+
+func (fct *FlagChangeTrackerImpl) RemoveFlagValueChangeListener(listener <-chan model.FSFlagValueChangeEvent) {
+	fct.lock.Lock()
+	var removed *flagValueListener
+	for i, valueListener := range fct.valueListeners {
+		if valueListener.channel == listener {
+			fct.valueListeners = append(fct.valueListeners[:i:i], fct.valueListeners[i+1:]...)
+			removed = valueListener
+			break
+		}
+	}
+	fct.lock.Unlock()
+
+	if removed != nil {
+		removed.lock.Lock()
+		removed.closed = true
+		close(removed.channel)
+		removed.lock.Unlock()
+	}
+}
+
+// dispatch works on a copy of the listeners, so adding or removing one never waits for a dispatch
+func (fct *FlagChangeTrackerImpl) dispatch(flagIds []string) {
+	if len(flagIds) == 0 {
+		return
+	}
+	fct.lock.Lock()
+	flagListeners := append([]*flagListener{}, fct.flagListeners...)
+	valueListeners := append([]*flagValueListener{}, fct.valueListeners...)
+	fct.lock.Unlock()
+
+	for _, flagId := range flagIds {
+		for _, listener := range flagListeners {
+			listener.send(model.FSFlagChangeEvent{FlagId: flagId})
+		}
+		for _, listener := range valueListeners {
+			if listener.flagId != flagId {
+				continue
+			}
+			newValue := fct.currentVariation(flagId, listener.user)
+			if reflect.DeepEqual(newValue, listener.lastValue) {
+				continue
+			}
+			// the old value is always the last one the listener received
+			if listener.send(model.FSFlagValueChangeEvent{
+				FlagId:   flagId,
+				OldValue: listener.lastValue,
+				NewValue: newValue,
+			}) {
+				listener.lastValue = newValue
+			}
+		}
+	}
+}
+
+func (fl *flagListener) send(event model.FSFlagChangeEvent) bool {
+	fl.lock.Lock()
+	defer fl.lock.Unlock()
+	if fl.closed {
+		return false
+	}
+	select {
+	case fl.channel <- event:
+		return true
+	default:
+		return false
+	}
+}
+
+func (fvl *flagValueListener) send(event model.FSFlagValueChangeEvent) bool {
+	fvl.lock.Lock()
+	defer fvl.lock.Unlock()
+	if fvl.closed {
+		return false
+	}
+	select {
+	case fvl.channel <- event:
+		return true
+	default:
+		return false
+	}
+}
+
+// currentVariation evaluates without recording events, a missing or broken flag has an empty variation
+func (fct *FlagChangeTrackerImpl) currentVariation(flagId string, user model.FSUser) model.FSVariation {
+	flagDTO, ok := fct.Store.GetFlag(flagId)
+	if !ok {
+		return model.FSVariation{}
+	}
+	userVariantDTO, err := evaluateWithoutEvents(fct.UserVariantService, flagDTO, user)
+	if err != nil {
+		return model.FSVariation{}
+	}
+	return model.FSVariation{
+		Key:   userVariantDTO.Key,
+		Value: userVariantDTO.Value,
+	}
+}
+
+// changedFlags returns the flags which changed themselves or depend on a changed segment
+func changedFlags(previous *dto.Data, current *dto.Data) []string {
+	changedSegments := map[string]bool{}
+	for segmentId, segment := range current.Segments {
+		previousSegment, ok := previous.Segments[segmentId]
+		if !ok || !reflect.DeepEqual(previousSegment, segment) {
+			changedSegments[segmentId] = true
+		}
+	}
+	for segmentId := range previous.Segments {
+		if _, ok := current.Segments[segmentId]; !ok {
+			changedSegments[segmentId] = true
+		}
+	}
+
+	changed := []string{}
+	for flagId, flag := range current.Flags {
+		previousFlag, ok := previous.Flags[flagId]
+		if !ok || !reflect.DeepEqual(previousFlag, flag) || dependsOnAny(flag, changedSegments) {
+			changed = append(changed, flagId)
+		}
+	}
+	for flagId := range previous.Flags {
+		if _, ok := current.Flags[flagId]; !ok {
+			changed = append(changed, flagId)
+		}
+	}
+	return changed
+}
+
+func dependsOnAny(flag dto.FlagDTO, segmentIds map[string]bool) bool {
+	if len(segmentIds) == 0 {
+		return false
+	}
+	for _, segmentId := range flag.EnvData.PreRequisites {
+		if segmentIds[segmentId] {
+			return true
+		}
+	}
+	for segmentId := range flag.EnvData.TargetSegments {
+		if segmentIds[segmentId] {
+			return true
+		}
+	}
+	for _, segmentId := range flag.EnvData.TargetSegmentsOrder {
+		if segmentIds[segmentId] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package impl_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/flagsense/go-sdk/pkg/fstestdata"
+	"github.com/flagsense/go-sdk/pkg/model"
+)
+
+func waitFor(t *testing.T, name string, done <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("%s blocked", name)
+	}
+}
+
+func TestUnreadListenerDoesNotBlockUpdates(t *testing.T) {
+	td := fstestdata.New()
+	td.Update(td.Flag("flag").StringFlag().Variations("a", "b").FallthroughVariation("a"))
+	service := td.NewService()
+	defer service.Close()
+
+	listener := service.AddFlagChangeListener()
+	valueListener := service.AddFlagValueChangeListener("flag", model.FSUser{UserId: "user"})
+
+	updated := make(chan struct{})
+	go func() {
+		for i := 0; i < 300; i++ {
+			value := "a"
+			if i%2 == 0 {
+				value = "b"
+			}
+			td.Update(td.Flag("flag").StringFlag().Variations("a", "b").FallthroughVariation(value))
+		}
+		close(updated)
+	}()
+	waitFor(t, "updates", updated)
+
+	removed := make(chan struct{})
+	go func() {
+		service.RemoveFlagChangeListener(listener)
+		service.RemoveFlagValueChangeListener(valueListener)
+		close(removed)
+	}()
+	waitFor(t, "removing listeners", removed)
+
+	updatedAgain := make(chan struct{})
+	go func() {
+		td.Update(td.Flag("flag").StringFlag().Variations("a", "b").FallthroughVariation("b"))
+		service.DataSourceStatus()
+		close(updatedAgain)
+	}()
+	waitFor(t, "update after removing listeners", updatedAgain)
+}
+
+func TestFlagValueChangeListener(t *testing.T) {
+	td := fstestdata.New()
+	td.Update(td.Flag("flag").StringFlag().Variations("a", "b").FallthroughVariation("a"))
+	service := td.NewService()
+	defer service.Close()
+
+	listener := service.AddFlagValueChangeListener("flag", model.FSUser{UserId: "user"})
+	td.Update(td.Flag("flag").StringFlag().Variations("a", "b").FallthroughVariation("b"))
+
+	select {
+	case event := <-listener:
+		if event.FlagId != "flag" || event.OldValue.Value != "a" || event.NewValue.Value != "b" {
+			t.Fatalf("unexpected event %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no value change event")
+	}
+	service.RemoveFlagValueChangeListener(listener)
+	if _, open := <-listener; open {
+		t.Fatal("listener not closed")
+	}
+}
+
+func TestListenersAddedAndRemovedDuringUpdates(t *testing.T) {
+	td := fstestdata.New()
+	td.Update(td.Flag("flag").BooleanFlag())
+	service := td.NewService()
+	defer service.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				listener := service.AddFlagChangeListener()
+				valueListener := service.AddFlagValueChangeListener("flag", model.FSUser{UserId: fmt.Sprint("user", i)})
+				service.RemoveFlagChangeListener(listener)
+				service.RemoveFlagValueChangeListener(valueListener)
+			}
+		}(i)
+	}
+	for i := 0; i < 50; i++ {
+		td.Update(td.Flag("flag").BooleanFlag().On(i%2 == 0))
+	}
+	wg.Wait()
+}
@@ -1,7 +1,9 @@
 package impl
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"github.com/flagsense/go-sdk/config"
 	"github.com/flagsense/go-sdk/pkg/dto"
@@ -13,16 +15,18 @@ import (
 	"github.com/flagsense/go-sdk/third_party/logger"
 	teltech "github.com/teltech/logger"
 	"strings"
-	"time"
 )
 
 type FlagsenseServiceImpl struct {
 	SDKConfig          *model.SDKConfig
-	Data               *dto.Data
+	Store              services.FeatureStore
 	DataPollerService  services.DataPollerService
+	DataUpdater        *DataUpdater
 	UserVariantService services.UserVariantService
 	EventService       services.EventService
+	FlagChangeTracker  *FlagChangeTrackerImpl
 	logger             *teltech.Log
+	cancel             context.CancelFunc
 }
 
 const (
@@ -31,62 +35,128 @@ const (
 )
 
 func NewFlagsenseService(sdkId string, sdkSecret string, environment *enums.Environment) *FlagsenseServiceImpl {
+	return NewFlagsenseServiceWithOptions(sdkId, sdkSecret, environment, Options{})
+}
+
+func NewFlagsenseServiceWithOptions(sdkId string, sdkSecret string, environment *enums.Environment, options Options) *FlagsenseServiceImpl {
 	// ---------------------  Initialize drivers  --------------------- //
 	manager := assetmnger.NewManager()
 	store := config.NewConfig(manager)
+	options.applyToStore(store)
 	sdkConfig := model.NewSDKConfig(sdkId, sdkSecret, environment)
-	ctx := context.Background()
-	log := logger.NewLogger()
-	httpClient := httptrp.NewFlagSenseHttpClient()
+	ctx, cancel := context.WithCancel(context.Background())
+	log := options.Logger
+	if log == nil {
+		log = logger.NewLogger()
+	}
+	httpClient := options.HttpClient
+	if httpClient == nil {
+		httpClient = httptrp.NewFlagSenseHttpClient()
+	}
 
 	// ---------------------  Initialize Data  --------------------- //
-	data := dto.Data{
-		LastUpdatedOn: ZER0,
-		Segments:      nil,
-		Flags:         nil,
+	var flagStore services.FeatureStore = NewInMemoryFeatureStore()
+	if options.FeatureStore != nil {
+		flagStore = options.FeatureStore
 	}
-
-	// ---------------------  Initialize Poller  --------------------- //
-	poller := NewDataPollerService(
-		sdkConfig, time.Duration(store.Constants.PollingInterval)*time.Minute, log, store, httpClient, &data)
-	go poller.Start(ctx)
+	dataUpdater := NewDataUpdater(flagStore)
 
 	// --------------------- Init User Variant ---------------------//
-	userVariantService := NewUserVariantService(&data, log)
+	userVariantService := NewUserVariantService(flagStore, log)
+	if options.Clock != nil {
+		userVariantService.Clock = options.Clock
+	}
+	if options.Prerelease != "" {
+		userVariantService.Prerelease = options.Prerelease
+	}
+
+	// --------------------- Init Flag Change Tracker ---------------------//
+	flagChangeTracker := NewFlagChangeTracker(flagStore, userVariantService)
+	if observer, ok := flagStore.(services.FeatureStoreObserver); ok {
+		observer.SetUpdateListener(flagChangeTracker.DataUpdated)
+	}
+	go flagChangeTracker.Start(ctx)
+
+	// ---------------------  Initialize Poller  --------------------- //
+	var dataSource services.DataPollerService
+	if options.DataSourceFactory != nil {
+		dataSource = options.DataSourceFactory.CreateDataSource(dataUpdater)
+	} else if options.DaemonMode {
+		dataSource = NewDaemonDataService(flagStore, options.PollingInterval, dataUpdater)
+	} else if options.DataFile != "" {
+		dataSource = NewFileDataService(options.DataFile, options.WatchDataFile, 0, log, dataUpdater)
+	} else {
+		poller := NewDataPollerService(
+			sdkConfig, options.pollingInterval(store), log, store, httpClient, dataUpdater)
+		if options.CacheFile != "" {
+			poller.Cache = NewDataCache(options.CacheFile, sdkConfig)
+			poller.LoadCache()
+		}
+		dataSource = poller
+		if options.Streaming {
+			dataSource = NewStreamingService(sdkConfig, options.StreamingUrl, log, store, httpClient, poller)
+		}
+	}
+	go dataSource.Start(ctx)
 
 	// --------------------- Init Events Service ---------------------//
-	eventsService := NewEventService(log, sdkConfig, httpClient, store)
+	eventsService := NewEventService(log, sdkConfig, httpClient, store, options.eventFlushInterval())
 	go eventsService.Start(ctx)
 
 	flagsense := &FlagsenseServiceImpl{
 		SDKConfig:          sdkConfig,
-		Data:               &data,
-		DataPollerService:  poller,
+		Store:              flagStore,
+		DataPollerService:  dataSource,
+		DataUpdater:        dataUpdater,
 		UserVariantService: userVariantService,
 		EventService:       eventsService,
+		FlagChangeTracker:  flagChangeTracker,
 		logger:             log,
+		cancel:             cancel,
 	}
 	return flagsense
 }
 
 func (fs *FlagsenseServiceImpl) WaitForInitializationComplete() {
-	fs.DataPollerService.WaitForInitializationComplete()
+	fs.DataUpdater.WaitForInitializationComplete()
+}
+
+func (fs *FlagsenseServiceImpl) WaitForInitialization(ctx context.Context) error {
+	return fs.DataUpdater.WaitForInitialization(ctx)
+}
+
+func (fs *FlagsenseServiceImpl) InitializationError() error {
+	return fs.DataUpdater.InitializationError()
+}
+
+func (fs *FlagsenseServiceImpl) DataSourceStatus() model.FSDataSourceStatus {
+	return fs.DataUpdater.GetStatus()
+}
+
+func (fs *FlagsenseServiceImpl) GetDataSourceStatusProvider() services.DataSourceStatusProvider {
+	return fs.DataUpdater
 }
 
 func (fs *FlagsenseServiceImpl) InitializationComplete() bool {
-	return fs.Data.LastUpdatedOn > ZER0
+	return fs.Store.IsInitialized()
 }
 
-func (fs *FlagsenseServiceImpl) Close()  {
+func (fs *FlagsenseServiceImpl) Close() {
 	ctx, cancel := context.WithCancel(context.Background())
 	fs.EventService.ShutdownHook(ctx)
 	cancel()
+	fs.DataUpdater.Close()
+	fs.cancel()
 }
 
-func (fs *FlagsenseServiceImpl) __evaluate(variantDTO *dto.UserVariantDTO) {
+func (fs *FlagsenseServiceImpl) __evaluate(ctx context.Context, variantDTO *dto.UserVariantDTO) {
 	var err error
-	if fs.Data.LastUpdatedOn == 0 {
+	if ctx.Err() != nil {
+		err = ctx.Err()
+		variantDTO.Reason = model.NewErrorReason(model.ERROR_CONTEXT_DONE)
+	} else if !fs.Store.IsInitialized() {
 		err = errors.New("flag data is still loading, evaluation not called")
+		variantDTO.Reason = model.NewErrorReason(model.ERROR_CLIENT_NOT_READY)
 	} else {
 		err = fs.UserVariantService.GetUserVariant(variantDTO)
 	}
@@ -94,6 +164,9 @@ func (fs *FlagsenseServiceImpl) __evaluate(variantDTO *dto.UserVariantDTO) {
 		//fs.logger.Errorf("Error in evaluation: %+v", err)
 		variantDTO.Key = variantDTO.DefaultKey
 		variantDTO.Value = variantDTO.DefaultValue
+		if !variantDTO.Reason.IsError() {
+			variantDTO.Reason = model.NewErrorReason(model.ERROR_EXCEPTION)
+		}
 
 		defaultKey := DEFAULT
 		if strings.TrimSpace(variantDTO.Key) == "" {
@@ -106,7 +179,7 @@ func (fs *FlagsenseServiceImpl) __evaluate(variantDTO *dto.UserVariantDTO) {
 	fs.EventService.AddEvaluationCount(variantDTO.FlagId, variantDTO.Key)
 }
 
-func (fs *FlagsenseServiceImpl) _evaluate(fsFlag model.FSFlag, user model.FSUser, expectedVariantType string) model.FSVariation {
+func (fs *FlagsenseServiceImpl) _evaluate(ctx context.Context, fsFlag model.FSFlag, user model.FSUser, expectedVariantType string) (model.FSVariation, model.FSEvaluationReason) {
 	userVariantDTO := dto.UserVariantDTO{
 		FlagId:              fsFlag.FlagId,
 		UserId:              user.UserId,
@@ -115,99 +188,325 @@ func (fs *FlagsenseServiceImpl) _evaluate(fsFlag model.FSFlag, user model.FSUser
 		DefaultKey:          fsFlag.DefaultKey,
 		ExpectedVariantType: expectedVariantType,
 	}
-	fs.__evaluate(&userVariantDTO)
+	fs.__evaluate(ctx, &userVariantDTO)
 	return model.FSVariation{
-		userVariantDTO.Key,
-		userVariantDTO.Value,
-	}
+		Key:   userVariantDTO.Key,
+		Value: userVariantDTO.Value,
+	}, userVariantDTO.Reason
 }
 
-func (fs *FlagsenseServiceImpl) evaluateAndSetVariation(fsFlag model.FSFlag, user model.FSUser, expectedVariantType string, result *model.FSVariation) {
+// variationConverters convert the variant value to the go type returned for each flag type
+var variationConverters = map[string]func(*model.FSVariation) (model.FSVariation, error){
+	dto.BOOL:   (*model.FSVariation).ToBoolean,
+	dto.INT:    (*model.FSVariation).ToInteger,
+	dto.DOUBLE: (*model.FSVariation).ToDouble,
+	dto.STRING: (*model.FSVariation).ToString,
+	dto.JSON:   (*model.FSVariation).ToMap,
+}
+
+func (fs *FlagsenseServiceImpl) evaluateAndSetVariation(ctx context.Context, fsFlag model.FSFlag, user model.FSUser, expectedVariantType string,
+	convert func(*model.FSVariation) (model.FSVariation, error), result *model.FSEvaluationDetail) {
 	var err error
 	var variation model.FSVariation
+	var reason model.FSEvaluationReason
 
 	defer func() { //catch or finally
 		if panicErr := recover(); panicErr != nil { //catch
 			//fs.logger.Errorf("Panic: %v", panicErr)
 			fs.EventService.AddEvaluationCount(fsFlag.FlagId, fsFlag.DefaultKey)
 			fs.EventService.AddErrorsCount(fsFlag.FlagId)
+			result.Reason = model.NewErrorReason(model.ERROR_EXCEPTION)
 		}
 	}()
 
-	variation = fs._evaluate(fsFlag, user, expectedVariantType)
+	variation, reason = fs._evaluate(ctx, fsFlag, user, expectedVariantType)
+
+	variation, err = convert(&variation)
 
-	switch expectedVariantType {
-	case dto.BOOL:
-		variation, err = variation.ToBoolean()
-		break
-	case dto.INT:
-		variation, err = variation.ToInteger()
-		break
-	case dto.DOUBLE:
-		variation, err = variation.ToDouble()
-		break
-	case dto.STRING:
-		variation, err = variation.ToString()
-		break
-	case dto.JSON:
-		variation, err = variation.ToMap()
-		break
+	// a failed evaluation keeps its reason, even when the default it served is of the wrong type
+	if reason.IsError() {
+		if err == nil {
+			result.Key = variation.Key
+			result.Value = variation.Value
+		}
+		result.Reason = reason
+		return
 	}
+	if err != nil {
+		result.Reason = model.NewErrorReason(model.ERROR_WRONG_TYPE)
+		return
+	}
+	result.Key = variation.Key
+	result.Value = variation.Value
+	result.Reason = reason
+}
 
-	if err == nil {
-		result.Key = variation.Key
-		result.Value = variation.Value
+func (fs *FlagsenseServiceImpl) evaluateDetail(ctx context.Context, fsFlag model.FSFlag, user model.FSUser, expectedVariantType string) model.FSEvaluationDetail {
+	return fs.evaluateDetailAs(ctx, fsFlag, user, expectedVariantType, variationConverters[expectedVariantType])
+}
+
+func (fs *FlagsenseServiceImpl) evaluateDetailAs(ctx context.Context, fsFlag model.FSFlag, user model.FSUser, expectedVariantType string,
+	convert func(*model.FSVariation) (model.FSVariation, error)) model.FSEvaluationDetail {
+	var result *model.FSEvaluationDetail
+	result = &model.FSEvaluationDetail{
+		Key:    fsFlag.DefaultKey,
+		Value:  fsFlag.DefaultValue,
+		Reason: model.NewErrorReason(model.ERROR_EXCEPTION),
 	}
+	fs.evaluateAndSetVariation(ctx, fsFlag, user, expectedVariantType, convert, result)
+	return *result
 }
 
 func (fs *FlagsenseServiceImpl) BooleanVariation(fsFlag model.FSFlag, user model.FSUser) model.FSVariation {
-	var result *model.FSVariation
-	result = &model.FSVariation{
-		Key: fsFlag.DefaultKey,
-		Value: fsFlag.DefaultValue,
+	detail := fs.evaluateDetail(context.Background(), fsFlag, user, dto.BOOL)
+	return model.FSVariation{
+		Key:   detail.Key,
+		Value: detail.Value,
 	}
-	fs.evaluateAndSetVariation(fsFlag, user, dto.BOOL, result)
-	return *result
 }
 
 func (fs *FlagsenseServiceImpl) StringVariation(fsFlag model.FSFlag, user model.FSUser) model.FSVariation {
-	var result *model.FSVariation
-	result = &model.FSVariation{
-		Key: fsFlag.DefaultKey,
-		Value: fsFlag.DefaultValue,
+	detail := fs.evaluateDetail(context.Background(), fsFlag, user, dto.STRING)
+	return model.FSVariation{
+		Key:   detail.Key,
+		Value: detail.Value,
 	}
-	fs.evaluateAndSetVariation(fsFlag, user, dto.STRING, result)
-	return *result
 }
 
 func (fs *FlagsenseServiceImpl) IntegerVariation(fsFlag model.FSFlag, user model.FSUser) model.FSVariation {
-	var result *model.FSVariation
-	result = &model.FSVariation{
-		Key: fsFlag.DefaultKey,
-		Value: fsFlag.DefaultValue,
+	detail := fs.evaluateDetail(context.Background(), fsFlag, user, dto.INT)
+	return model.FSVariation{
+		Key:   detail.Key,
+		Value: detail.Value,
 	}
-	fs.evaluateAndSetVariation(fsFlag, user, dto.INT, result)
-	return *result
 }
 
 func (fs *FlagsenseServiceImpl) DecimalVariation(fsFlag model.FSFlag, user model.FSUser) model.FSVariation {
-	var result *model.FSVariation
-	result = &model.FSVariation{
-		Key: fsFlag.DefaultKey,
-		Value: fsFlag.DefaultValue,
+	detail := fs.evaluateDetail(context.Background(), fsFlag, user, dto.DOUBLE)
+	return model.FSVariation{
+		Key:   detail.Key,
+		Value: detail.Value,
 	}
-	fs.evaluateAndSetVariation(fsFlag, user, dto.DOUBLE, result)
-	return *result
 }
 
 func (fs *FlagsenseServiceImpl) MapVariation(fsFlag model.FSFlag, user model.FSUser) model.FSVariation {
-	var result *model.FSVariation
-	result = &model.FSVariation{
-		Key: fsFlag.DefaultKey,
-		Value: fsFlag.DefaultValue,
+	detail := fs.evaluateDetail(context.Background(), fsFlag, user, dto.JSON)
+	return model.FSVariation{
+		Key:   detail.Key,
+		Value: detail.Value,
+	}
+}
+
+func (fs *FlagsenseServiceImpl) BooleanVariationDetail(fsFlag model.FSFlag, user model.FSUser) model.FSEvaluationDetail {
+	return fs.evaluateDetail(context.Background(), fsFlag, user, dto.BOOL)
+}
+
+func (fs *FlagsenseServiceImpl) StringVariationDetail(fsFlag model.FSFlag, user model.FSUser) model.FSEvaluationDetail {
+	return fs.evaluateDetail(context.Background(), fsFlag, user, dto.STRING)
+}
+
+func (fs *FlagsenseServiceImpl) IntegerVariationDetail(fsFlag model.FSFlag, user model.FSUser) model.FSEvaluationDetail {
+	return fs.evaluateDetail(context.Background(), fsFlag, user, dto.INT)
+}
+
+func (fs *FlagsenseServiceImpl) DecimalVariationDetail(fsFlag model.FSFlag, user model.FSUser) model.FSEvaluationDetail {
+	return fs.evaluateDetail(context.Background(), fsFlag, user, dto.DOUBLE)
+}
+
+func (fs *FlagsenseServiceImpl) MapVariationDetail(fsFlag model.FSFlag, user model.FSUser) model.FSEvaluationDetail {
+	return fs.evaluateDetail(context.Background(), fsFlag, user, dto.JSON)
+}
+
+func (fs *FlagsenseServiceImpl) BooleanVariationCtx(ctx context.Context, fsFlag model.FSFlag, user model.FSUser) model.FSVariation {
+	detail := fs.evaluateDetail(ctx, fsFlag, user, dto.BOOL)
+	return model.FSVariation{
+		Key:   detail.Key,
+		Value: detail.Value,
+	}
+}
+
+func (fs *FlagsenseServiceImpl) StringVariationCtx(ctx context.Context, fsFlag model.FSFlag, user model.FSUser) model.FSVariation {
+	detail := fs.evaluateDetail(ctx, fsFlag, user, dto.STRING)
+	return model.FSVariation{
+		Key:   detail.Key,
+		Value: detail.Value,
+	}
+}
+
+func (fs *FlagsenseServiceImpl) IntegerVariationCtx(ctx context.Context, fsFlag model.FSFlag, user model.FSUser) model.FSVariation {
+	detail := fs.evaluateDetail(ctx, fsFlag, user, dto.INT)
+	return model.FSVariation{
+		Key:   detail.Key,
+		Value: detail.Value,
 	}
-	fs.evaluateAndSetVariation(fsFlag, user, dto.JSON, result)
-	return *result
+}
+
+func (fs *FlagsenseServiceImpl) DecimalVariationCtx(ctx context.Context, fsFlag model.FSFlag, user model.FSUser) model.FSVariation {
+	detail := fs.evaluateDetail(ctx, fsFlag, user, dto.DOUBLE)
+	return model.FSVariation{
+		Key:   detail.Key,
+		Value: detail.Value,
+	}
+}
+
+func (fs *FlagsenseServiceImpl) MapVariationCtx(ctx context.Context, fsFlag model.FSFlag, user model.FSUser) model.FSVariation {
+	detail := fs.evaluateDetail(ctx, fsFlag, user, dto.JSON)
+	return model.FSVariation{
+		Key:   detail.Key,
+		Value: detail.Value,
+	}
+}
+
+func (fs *FlagsenseServiceImpl) BooleanVariationDetailCtx(ctx context.Context, fsFlag model.FSFlag, user model.FSUser) model.FSEvaluationDetail {
+	return fs.evaluateDetail(ctx, fsFlag, user, dto.BOOL)
+}
+
+func (fs *FlagsenseServiceImpl) StringVariationDetailCtx(ctx context.Context, fsFlag model.FSFlag, user model.FSUser) model.FSEvaluationDetail {
+	return fs.evaluateDetail(ctx, fsFlag, user, dto.STRING)
+}
+
+func (fs *FlagsenseServiceImpl) IntegerVariationDetailCtx(ctx context.Context, fsFlag model.FSFlag, user model.FSUser) model.FSEvaluationDetail {
+	return fs.evaluateDetail(ctx, fsFlag, user, dto.INT)
+}
+
+// Int64Variation evaluates an INT flag without narrowing the value to int32
+func (fs *FlagsenseServiceImpl) Int64Variation(fsFlag model.FSFlag, user model.FSUser) model.FSVariation {
+	return fs.Int64VariationCtx(context.Background(), fsFlag, user)
+}
+
+func (fs *FlagsenseServiceImpl) Int64VariationDetail(fsFlag model.FSFlag, user model.FSUser) model.FSEvaluationDetail {
+	return fs.Int64VariationDetailCtx(context.Background(), fsFlag, user)
+}
+
+func (fs *FlagsenseServiceImpl) Int64VariationCtx(ctx context.Context, fsFlag model.FSFlag, user model.FSUser) model.FSVariation {
+	detail := fs.Int64VariationDetailCtx(ctx, fsFlag, user)
+	return model.FSVariation{
+		Key:   detail.Key,
+		Value: detail.Value,
+	}
+}
+
+func (fs *FlagsenseServiceImpl) Int64VariationDetailCtx(ctx context.Context, fsFlag model.FSFlag, user model.FSUser) model.FSEvaluationDetail {
+	return fs.evaluateDetailAs(ctx, fsFlag, user, dto.INT, (*model.FSVariation).ToInt64)
+}
+
+func (fs *FlagsenseServiceImpl) DecimalVariationDetailCtx(ctx context.Context, fsFlag model.FSFlag, user model.FSUser) model.FSEvaluationDetail {
+	return fs.evaluateDetail(ctx, fsFlag, user, dto.DOUBLE)
+}
+
+func (fs *FlagsenseServiceImpl) MapVariationDetailCtx(ctx context.Context, fsFlag model.FSFlag, user model.FSUser) model.FSEvaluationDetail {
+	return fs.evaluateDetail(ctx, fsFlag, user, dto.JSON)
+}
+
+// JSONVariation decodes the variant of a JSON flag into out. The decoding is strict, a variant with a field that out
+// does not have fails with ERROR_DECODE_FAILED and the default value is decoded into out instead.
+func (fs *FlagsenseServiceImpl) JSONVariation(fsFlag model.FSFlag, user model.FSUser, out interface{}) model.FSEvaluationDetail {
+	return fs.JSONVariationCtx(context.Background(), fsFlag, user, out)
+}
+
+// JSONVariationCtx decodes the variant of a JSON flag into out, which can be any value encoding/json decodes into,
+// also for array and scalar variants. Fields of the variant missing in out fail the decoding. The default value
+// is decoded into out whenever the evaluation or the decoding fails.
+func (fs *FlagsenseServiceImpl) JSONVariationCtx(ctx context.Context, fsFlag model.FSFlag, user model.FSUser, out interface{}) (result model.FSEvaluationDetail) {
+	result = model.FSEvaluationDetail{
+		Key:    fsFlag.DefaultKey,
+		Value:  fsFlag.DefaultValue,
+		Reason: model.NewErrorReason(model.ERROR_EXCEPTION),
+	}
+	defer func() { //catch or finally
+		if panicErr := recover(); panicErr != nil { //catch
+			//fs.logger.Errorf("Panic: %v", panicErr)
+			fs.EventService.AddEvaluationCount(fsFlag.FlagId, fsFlag.DefaultKey)
+			fs.EventService.AddErrorsCount(fsFlag.FlagId)
+			decodeJSON(fsFlag.DefaultValue, out)
+			result.Reason = model.NewErrorReason(model.ERROR_EXCEPTION)
+		}
+	}()
+
+	variation, reason := fs._evaluate(ctx, fsFlag, user, dto.JSON)
+	if reason.IsError() {
+		decodeJSON(fsFlag.DefaultValue, out)
+		result.Reason = reason
+		return result
+	}
+	if err := decodeJSON(variation.Value, out); err != nil {
+		fs.logger.Errorf("error while decoding flag:%s, error:%+v", fsFlag.FlagId, err)
+		fs.EventService.AddErrorsCount(fsFlag.FlagId)
+		decodeJSON(fsFlag.DefaultValue, out)
+		result.Reason = model.NewErrorReason(model.ERROR_DECODE_FAILED)
+		return result
+	}
+	result.Key = variation.Key
+	result.Value = variation.Value
+	result.Reason = reason
+	return result
+}
+
+// flagStateConverter returns values of the types the variations return, INT as int32 like IntegerVariation.
+// JSON values are kept as decoded, they are not always maps.
+func flagStateConverter(flagType string) func(*model.FSVariation) (model.FSVariation, error) {
+	if flagType == dto.JSON {
+		return nil
+	}
+	return variationConverters[flagType]
+}
+
+func (fs *FlagsenseServiceImpl) AllFlagsState(user model.FSUser, options model.FSAllFlagsStateOptions) model.FSAllFlagsState {
+	data := fs.Store.All()
+	state := model.FSAllFlagsState{
+		Valid:         fs.Store.IsInitialized(),
+		LastUpdatedOn: data.LastUpdatedOn,
+		Flags:         make(map[string]model.FSFlagState, len(data.Flags)),
+	}
+	if !state.Valid {
+		return state
+	}
+
+	for flagId, flagDTO := range data.Flags {
+		if options.ClientSideOnly && !flagDTO.ClientSide {
+			continue
+		}
+		userVariantDTO, err := evaluateWithoutEvents(fs.UserVariantService, flagDTO, user)
+		variation := model.FSVariation{
+			Key:   userVariantDTO.Key,
+			Value: userVariantDTO.Value,
+		}
+		if convert := flagStateConverter(flagDTO.Type); err == nil && convert != nil {
+			if variation, err = convert(&variation); err != nil {
+				userVariantDTO.Reason = model.NewErrorReason(model.ERROR_WRONG_TYPE)
+			}
+		}
+		flagState := model.FSFlagState{
+			Key:   variation.Key,
+			Value: variation.Value,
+		}
+		// a failed flag keeps the variant it evaluated to, only its value is dropped
+		if err != nil {
+			flagState = model.FSFlagState{Key: userVariantDTO.Key}
+		}
+		if options.WithReasons {
+			reason := userVariantDTO.Reason
+			flagState.Reason = &reason
+		}
+		state.Flags[flagId] = flagState
+	}
+	return state
+}
+
+func (fs *FlagsenseServiceImpl) AddFlagChangeListener() <-chan model.FSFlagChangeEvent {
+	return fs.FlagChangeTracker.AddFlagChangeListener()
+}
+
+func (fs *FlagsenseServiceImpl) RemoveFlagChangeListener(listener <-chan model.FSFlagChangeEvent) {
+	fs.FlagChangeTracker.RemoveFlagChangeListener(listener)
+}
+
+func (fs *FlagsenseServiceImpl) AddFlagValueChangeListener(flagId string, user model.FSUser) <-chan model.FSFlagValueChangeEvent {
+	return fs.FlagChangeTracker.AddFlagValueChangeListener(flagId, user)
+}
+
+func (fs *FlagsenseServiceImpl) RemoveFlagValueChangeListener(listener <-chan model.FSFlagValueChangeEvent) {
+	fs.FlagChangeTracker.RemoveFlagValueChangeListener(listener)
 }
 
 func (fs *FlagsenseServiceImpl) RecordCodeError(flagId string, variationKey string)  {
@@ -215,3 +514,14 @@ func (fs *FlagsenseServiceImpl) RecordCodeError(flagId string, variationKey stri
 		fs.EventService.AddCodeBugsCount(flagId, variationKey)
 	}
 }
+
+// decodeJSON fails when the value does not fit out, also for fields that out does not have
+func decodeJSON(value interface{}, out interface{}) error {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(out)
+}
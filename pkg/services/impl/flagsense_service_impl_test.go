@@ -0,0 +1,123 @@
+package impl_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/flagsense/go-sdk/constants"
+	"github.com/flagsense/go-sdk/pkg/enums"
+	"github.com/flagsense/go-sdk/pkg/fstestdata"
+	"github.com/flagsense/go-sdk/pkg/model"
+	"github.com/flagsense/go-sdk/pkg/services"
+	"github.com/flagsense/go-sdk/pkg/services/impl"
+)
+
+// newNotReadyService never gets any data, the sdk service keeps failing
+func newNotReadyService(t *testing.T) services.FlagsenseService {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	captureEvents := false
+	service := impl.NewFlagsenseServiceWithOptions("not-ready", "secret", enums.NewEnvironment(constants.DEV), impl.Options{
+		SDKServiceUrl: server.URL,
+		CaptureEvents: &captureEvents,
+	})
+	t.Cleanup(func() {
+		service.Close()
+		server.Close()
+	})
+	return service
+}
+
+func TestErrorReasonsWithADefaultOfTheWrongType(t *testing.T) {
+	td := fstestdata.New()
+	td.Update(td.Flag("bool-flag").BooleanFlag().FallthroughVariation(true))
+	td.Update(td.Flag("broken-int-flag").IntegerFlag().Variations("one").FallthroughVariation("one"))
+	service := newTestDataService(t, td, impl.Options{})
+
+	notReadyService := newNotReadyService(t)
+
+	tests := []struct {
+		name    string
+		service services.FlagsenseService
+		flagId  string
+		reason  model.FSEvaluationReason
+	}{
+		{"flag not found", service, "other", model.NewErrorReason(model.ERROR_FLAG_NOT_FOUND)},
+		{"client not ready", notReadyService, "bool-flag", model.NewErrorReason(model.ERROR_CLIENT_NOT_READY)},
+		{"flag of another type", service, "bool-flag", model.NewErrorReason(model.ERROR_WRONG_TYPE)},
+		{"variant of the wrong type", service, "broken-int-flag", model.NewErrorReason(model.ERROR_WRONG_TYPE)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			detail := test.service.IntegerVariationDetail(model.FSFlag{FlagId: test.flagId, DefaultValue: "default"}, model.FSUser{UserId: "user"})
+			if detail.Value != "default" || detail.Reason != test.reason {
+				t.Fatalf("expected the default with %+v, got %v %+v", test.reason, detail.Value, detail.Reason)
+			}
+		})
+	}
+}
+
+func TestAllFlagsState(t *testing.T) {
+	td := fstestdata.New()
+	td.Update(td.Flag("int-flag").IntegerFlag().Variations(5).FallthroughVariation(5))
+	td.Update(td.Flag("int64-flag").IntegerFlag().Variations(int64(3000000000)).FallthroughVariation(int64(3000000000)))
+	td.Update(td.Flag("broken-int-flag").IntegerFlag().Variations("one").FallthroughVariation("one"))
+	service := newTestDataService(t, td, impl.Options{})
+
+	state := service.AllFlagsState(model.FSUser{UserId: "user"}, model.FSAllFlagsStateOptions{WithReasons: true})
+	if !state.Valid {
+		t.Fatal("expected a valid state")
+	}
+	tests := []struct {
+		flagId string
+		key    string
+		value  interface{}
+		reason model.FSEvaluationReason
+	}{
+		{"int-flag", "5", int32(5), model.NewReason(model.REASON_FALLTHROUGH)},
+		{"int64-flag", "3000000000", nil, model.NewErrorReason(model.ERROR_WRONG_TYPE)},
+		{"broken-int-flag", "one", nil, model.NewErrorReason(model.ERROR_WRONG_TYPE)},
+	}
+	for _, test := range tests {
+		flagState, ok := state.GetFlagState(test.flagId)
+		if !ok || flagState.Key != test.key || flagState.Value != test.value || *flagState.Reason != test.reason {
+			t.Fatalf("%s: expected %s %v %+v, got %+v", test.flagId, test.key, test.value, test.reason, flagState)
+		}
+	}
+}
+
+func TestAllFlagsStateBeforeInitialization(t *testing.T) {
+	service := newNotReadyService(t)
+	if state := service.AllFlagsState(model.FSUser{UserId: "user"}, model.FSAllFlagsStateOptions{}); state.Valid || len(state.Flags) != 0 {
+		t.Fatalf("expected an invalid empty state, got %+v", state)
+	}
+}
+
+func TestJSONVariationDecodesStrictly(t *testing.T) {
+	type settings struct {
+		Color string `json:"color"`
+	}
+	td := fstestdata.New()
+	td.Update(td.Flag("settings").MapFlag().
+		Variations(map[string]interface{}{"color": "red"}).
+		FallthroughVariation(map[string]interface{}{"color": "red"}))
+	td.Update(td.Flag("extended-settings").MapFlag().
+		Variations(map[string]interface{}{"color": "red", "size": "big"}).
+		FallthroughVariation(map[string]interface{}{"color": "red", "size": "big"}))
+	service := newTestDataService(t, td, impl.Options{})
+	defaultValue := map[string]interface{}{"color": "blue"}
+
+	var decoded settings
+	detail := service.JSONVariation(model.FSFlag{FlagId: "settings", DefaultValue: defaultValue}, model.FSUser{UserId: "user"}, &decoded)
+	if decoded.Color != "red" || detail.Reason != model.NewReason(model.REASON_FALLTHROUGH) {
+		t.Fatalf("expected the variant, got %+v %+v", decoded, detail.Reason)
+	}
+
+	decoded = settings{}
+	detail = service.JSONVariation(model.FSFlag{FlagId: "extended-settings", DefaultValue: defaultValue}, model.FSUser{UserId: "user"}, &decoded)
+	if decoded.Color != "blue" || detail.Reason != model.NewErrorReason(model.ERROR_DECODE_FAILED) {
+		t.Fatalf("expected the default with a decode error, got %+v %+v", decoded, detail.Reason)
+	}
+}
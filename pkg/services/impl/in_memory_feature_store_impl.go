@@ -0,0 +1,89 @@
+package impl
+
+import (
+	"github.com/flagsense/go-sdk/pkg/dto"
+	"sync/atomic"
+)
+
+// InMemoryFeatureStoreImpl holds an immutable snapshot of the flags data. Writers swap the whole snapshot,
+// so a snapshot returned by All must never be modified.
+type InMemoryFeatureStoreImpl struct {
+	value          *atomic.Value
+	updateListener func(previous *dto.Data, current *dto.Data)
+}
+
+func NewInMemoryFeatureStore() *InMemoryFeatureStoreImpl {
+	value := &atomic.Value{}
+	value.Store(&dto.Data{
+		LastUpdatedOn: ZER0,
+		Segments:      nil,
+		Flags:         nil,
+	})
+	return &InMemoryFeatureStoreImpl{
+		value: value,
+	}
+}
+
+func (fs *InMemoryFeatureStoreImpl) Init(data *dto.Data) error {
+	previous := fs.All()
+	fs.value.Store(data)
+	if fs.updateListener != nil {
+		fs.updateListener(previous, data)
+	}
+	return nil
+}
+
+func (fs *InMemoryFeatureStoreImpl) GetFlag(flagId string) (dto.FlagDTO, bool) {
+	flag, ok := fs.All().Flags[flagId]
+	return flag, ok
+}
+
+func (fs *InMemoryFeatureStoreImpl) GetSegment(segmentId string) (dto.SegmentDTO, bool) {
+	segment, ok := fs.All().Segments[segmentId]
+	return segment, ok
+}
+
+func (fs *InMemoryFeatureStoreImpl) All() *dto.Data {
+	return fs.value.Load().(*dto.Data)
+}
+
+// Upsert must not be called concurrently with other writes, the data updater serializes all writers
+func (fs *InMemoryFeatureStoreImpl) Upsert(data *dto.Data) error {
+	return fs.Init(mergeData(fs.All(), data))
+}
+
+func (fs *InMemoryFeatureStoreImpl) IsInitialized() bool {
+	return fs.All().LastUpdatedOn > ZER0
+}
+
+// SetUpdateListener must be called before any data source is started, the listener is called on the writer's goroutine
+func (fs *InMemoryFeatureStoreImpl) SetUpdateListener(listener func(previous *dto.Data, current *dto.Data)) {
+	fs.updateListener = listener
+}
+
+// mergeData adds the flags and segments of data to a copy of current
+func mergeData(current *dto.Data, data *dto.Data) *dto.Data {
+	flags := make(map[string]dto.FlagDTO, len(current.Flags)+len(data.Flags))
+	for key, value := range current.Flags {
+		flags[key] = value
+	}
+	for key, value := range data.Flags {
+		flags[key] = value
+	}
+	segments := make(map[string]dto.SegmentDTO, len(current.Segments)+len(data.Segments))
+	for key, value := range current.Segments {
+		segments[key] = value
+	}
+	for key, value := range data.Segments {
+		segments[key] = value
+	}
+	lastUpdatedOn := data.LastUpdatedOn
+	if lastUpdatedOn < current.LastUpdatedOn {
+		lastUpdatedOn = current.LastUpdatedOn
+	}
+	return &dto.Data{
+		Segments:      segments,
+		Flags:         flags,
+		LastUpdatedOn: lastUpdatedOn,
+	}
+}
@@ -0,0 +1,53 @@
+package impl_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/flagsense/go-sdk/pkg/dto"
+	"github.com/flagsense/go-sdk/pkg/fstestdata"
+	"github.com/flagsense/go-sdk/pkg/model"
+)
+
+// TestEvaluationsDuringStoreSwaps is meant for go test -race, evaluations read the store while updates replace it
+func TestEvaluationsDuringStoreSwaps(t *testing.T) {
+	td := fstestdata.New()
+	td.UpdateSegment(td.Segment("beta").Rule("plan", dto.STRING, dto.EQ, "pro"))
+	td.Update(td.Flag("flag").StringFlag().Variations("a", "b").FallthroughVariation("a").VariationForSegment("beta", "b"))
+	service := td.NewService()
+	defer service.Close()
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			user := model.FSUser{UserId: "user", Attributes: map[string]interface{}{"plan": "pro"}}
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				value := service.StringVariation(model.FSFlag{FlagId: "flag", DefaultValue: "default"}, user).Value
+				if value != "a" && value != "b" {
+					t.Errorf("unexpected value %v", value)
+					return
+				}
+				service.AllFlagsState(user, model.FSAllFlagsStateOptions{WithReasons: true})
+			}
+		}()
+	}
+
+	for i := 0; i < 200; i++ {
+		value := "a"
+		if i%2 == 0 {
+			value = "b"
+		}
+		td.Update(td.Flag("flag").StringFlag().Variations("a", "b").FallthroughVariation(value).VariationForSegment("beta", "b"))
+		td.UpdateSegment(td.Segment("beta").Rule("plan", dto.STRING, dto.EQ, "pro", value))
+	}
+	close(stop)
+	wg.Wait()
+}
@@ -0,0 +1,75 @@
+package impl
+
+import (
+	"errors"
+	"github.com/flagsense/go-sdk/config"
+	"github.com/flagsense/go-sdk/pkg/services"
+	"github.com/teltech/logger"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var ErrDaemonModeWithoutStore = errors.New("daemon mode needs a persistent feature store populated by another process")
+
+type Options struct {
+	SDKServiceUrl      string
+	EventsServiceUrl   string
+	StreamingUrl       string
+	Streaming          bool
+	DataFile           string
+	WatchDataFile      bool
+	CacheFile          string
+	FeatureStore       services.FeatureStore
+	DaemonMode         bool
+	DataSourceFactory  services.DataSourceFactory
+	PollingInterval    time.Duration
+	EventFlushInterval time.Duration
+	CaptureEvents      *bool
+	Clock              func() time.Time
+	Prerelease         string
+	HttpClient         *http.Client
+	Logger             *logger.Log
+}
+
+func (o *Options) applyToStore(store *config.Store) {
+	if strings.TrimSpace(o.SDKServiceUrl) != "" {
+		store.Services.SDKService.HttpEndpoint.Url = strings.TrimRight(o.SDKServiceUrl, "/")
+	}
+	if strings.TrimSpace(o.EventsServiceUrl) != "" {
+		store.Services.EventsService.HttpEndpoint.Url = strings.TrimRight(o.EventsServiceUrl, "/")
+	}
+	if o.CaptureEvents != nil {
+		store.Constants.CaptureEvents = *o.CaptureEvents
+	}
+	// offline mode never talks to the flagsense servers
+	if o.DataFile != "" {
+		store.Constants.CaptureEvents = false
+	}
+}
+
+func (o *Options) pollingInterval(store *config.Store) time.Duration {
+	if o.PollingInterval > 0 {
+		return o.PollingInterval
+	}
+	return time.Duration(store.Constants.PollingInterval) * time.Minute
+}
+
+// eventFlushInterval is at least MIN_EVENT_FLUSH_INTERVAL, events are grouped in time slots of whole milliseconds
+func (o *Options) eventFlushInterval() time.Duration {
+	if o.EventFlushInterval > 0 && o.EventFlushInterval < MIN_EVENT_FLUSH_INTERVAL {
+		return MIN_EVENT_FLUSH_INTERVAL
+	}
+	if o.EventFlushInterval > 0 {
+		return o.EventFlushInterval
+	}
+	return EVENT_FLUSH_INTERVAL * time.Minute
+}
+
+// Validate reports options which can never work together
+func (o *Options) Validate() error {
+	if o.DaemonMode && o.DataSourceFactory == nil && !isPersistentStore(o.FeatureStore) {
+		return ErrDaemonModeWithoutStore
+	}
+	return nil
+}
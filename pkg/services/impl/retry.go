@@ -0,0 +1,78 @@
+package impl
+
+import (
+	"errors"
+	"github.com/flagsense/go-sdk/pkg/infrastructure/http"
+	"math/rand"
+	"time"
+)
+
+const (
+	RETRY_INITIAL_DELAY = 1 * time.Second
+	RETRY_MAX_DELAY     = 30 * time.Second
+
+	RETRY_MAX_POLLING_DELAY = 10 * time.Minute
+)
+
+// unrecoverableError marks an error after which the data source stops, so waiting for initialization ends
+type unrecoverableError struct {
+	err error
+}
+
+func (e *unrecoverableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *unrecoverableError) Unwrap() error {
+	return e.err
+}
+
+func isUnrecoverable(err error) bool {
+	var stopped *unrecoverableError
+	if errors.As(err, &stopped) {
+		return true
+	}
+	var statusError *httptrp.HttpStatusError
+	return errors.As(err, &statusError) && statusError.IsUnrecoverable()
+}
+
+func retryAfter(err error) time.Duration {
+	var statusError *httptrp.HttpStatusError
+	if errors.As(err, &statusError) {
+		return statusError.RetryAfter
+	}
+	return 0
+}
+
+// retriableDelay is how long to wait before polling again after err, zero to poll at the polling interval.
+// 429 and 5xx responses are never retried sooner than the polling interval, consecutive ones double the delay
+// up to RETRY_MAX_POLLING_DELAY, and a longer Retry-After of the server wins.
+func retriableDelay(err error, failures int, pollingInterval time.Duration) time.Duration {
+	var statusError *httptrp.HttpStatusError
+	if !errors.As(err, &statusError) || !statusError.IsRetriable() {
+		return 0
+	}
+	delay := pollingInterval
+	for i := 1; i < failures && delay < RETRY_MAX_POLLING_DELAY; i++ {
+		delay *= 2
+	}
+	if delay > RETRY_MAX_POLLING_DELAY && pollingInterval <= RETRY_MAX_POLLING_DELAY {
+		delay = RETRY_MAX_POLLING_DELAY
+	}
+	if statusError.RetryAfter > delay {
+		delay = statusError.RetryAfter
+	}
+	return delay
+}
+
+func retryDelay(attempt int) time.Duration {
+	delay := RETRY_INITIAL_DELAY
+	for i := 0; i < attempt && delay < RETRY_MAX_DELAY; i++ {
+		delay *= 2
+	}
+	if delay > RETRY_MAX_DELAY {
+		delay = RETRY_MAX_DELAY
+	}
+	// jitter between half and the full delay so that a fleet does not retry in lockstep
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
@@ -0,0 +1,291 @@
+package impl
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/flagsense/go-sdk/config"
+	"github.com/flagsense/go-sdk/pkg/dto"
+	"github.com/flagsense/go-sdk/pkg/infrastructure/http"
+	"github.com/flagsense/go-sdk/pkg/model"
+	"github.com/teltech/logger"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ACCEPT            = "accept"
+	TEXT_EVENT_STREAM = "text/event-stream"
+
+	EVENT_PUT    = "put"
+	EVENT_PATCH  = "patch"
+	EVENT_DELETE = "delete"
+
+	FLAGS_PATH_PREFIX    = "/flags/"
+	SEGMENTS_PATH_PREFIX = "/segments/"
+
+	STREAM_READ_TIMEOUT = 5 * time.Minute
+)
+
+type StreamingServiceImpl struct {
+	SDKConfig      *model.SDKConfig
+	StreamUrl      string
+	logger         *logger.Log
+	config         *config.Store
+	client         *http.Client
+	Poller         *DataPollerServiceImpl
+	fallbackCancel context.CancelFunc
+	fallbackLock   *sync.Mutex
+}
+
+type streamPatch struct {
+	Path          string          `json:"path"`
+	Data          json.RawMessage `json:"data"`
+	LastUpdatedOn float64         `json:"lastUpdatedOn"`
+}
+
+type sseEvent struct {
+	Name string
+	Data string
+}
+
+func NewStreamingService(sdkConfig *model.SDKConfig, streamUrl string, logger *logger.Log, config *config.Store,
+	client *http.Client, poller *DataPollerServiceImpl) *StreamingServiceImpl {
+	if strings.TrimSpace(streamUrl) == "" {
+		streamUrl = fmt.Sprintf("%s/stream", config.Services.SDKService.HttpEndpoint.Url)
+	}
+	// the stream is long lived, so the request timeout of the polling client can not be applied to it
+	streamClient := *client
+	streamClient.Timeout = 0
+	return &StreamingServiceImpl{
+		SDKConfig:    sdkConfig,
+		StreamUrl:    streamUrl,
+		logger:       logger,
+		config:       config,
+		client:       &streamClient,
+		Poller:       poller,
+		fallbackLock: &sync.Mutex{},
+	}
+}
+
+func (ss *StreamingServiceImpl) Start(ctx context.Context) {
+	attempt := 0
+	for {
+		connected, err := ss.consumeStream(ctx)
+		if ctx.Err() != nil {
+			ss.stopFallback()
+			return
+		}
+		if err != nil {
+			ss.logger.Errorf("flagsense stream interrupted, error:%+v", err)
+			ss.Poller.SetError(err)
+		}
+		if isUnrecoverable(err) {
+			ss.stopFallback()
+			return
+		}
+		if connected {
+			attempt = 0
+		}
+		ss.startFallback(ctx)
+
+		delay := retryDelay(attempt)
+		if retryAfter := retryAfter(err); retryAfter > delay {
+			delay = retryAfter
+		}
+		select {
+		case <-time.After(delay):
+			attempt++
+		case <-ctx.Done():
+			ss.stopFallback()
+			return
+		}
+	}
+}
+
+func (ss *StreamingServiceImpl) consumeStream(ctx context.Context) (bool, error) {
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	endpoint := fmt.Sprintf("%s?environment=%s", ss.StreamUrl, url.QueryEscape(ss.SDKConfig.Environment))
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set(ACCEPT, TEXT_EVENT_STREAM)
+	req.Header.Set(HEADER_AUTH_TYPE, SDK)
+	req.Header.Set(HEADER_SDK_ID, ss.SDKConfig.SDKId)
+	req.Header.Set(HEADER_SDK_SECRET, ss.SDKConfig.SDKSecret)
+
+	response, err := ss.client.Do(req.WithContext(streamCtx))
+	if err != nil {
+		return false, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return false, httptrp.NewHttpStatusError(response, ss.StreamUrl, nil)
+	}
+
+	// cancel the request if the server goes quiet, heartbeat comments also reset the timer
+	readTimer := time.AfterFunc(STREAM_READ_TIMEOUT, cancel)
+	defer readTimer.Stop()
+
+	connected := false
+	reader := bufio.NewReader(response.Body)
+	event := sseEvent{}
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if err == io.EOF {
+				err = errors.New("stream closed by server")
+			}
+			return connected, err
+		}
+		readTimer.Reset(STREAM_READ_TIMEOUT)
+
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			if event.Data != "" {
+				if err := ss.applyEvent(event); err != nil {
+					ss.logger.Errorf("error while applying stream event:%s, error:%+v", event.Name, err)
+				} else if !connected {
+					connected = true
+					ss.stopFallback()
+				}
+			}
+			event = sseEvent{}
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value := line, ""
+		if index := strings.Index(line, ":"); index >= 0 {
+			field = line[:index]
+			value = strings.TrimPrefix(line[index+1:], " ")
+		}
+		switch field {
+		case "event":
+			event.Name = value
+		case "data":
+			if event.Data != "" {
+				event.Data += "\n"
+			}
+			event.Data += value
+		}
+	}
+}
+
+func (ss *StreamingServiceImpl) applyEvent(event sseEvent) error {
+	err := ss.updateData(event)
+	if err == nil {
+		ss.Poller.saveCache()
+	}
+	return err
+}
+
+func (ss *StreamingServiceImpl) updateData(event sseEvent) error {
+	switch event.Name {
+	case EVENT_PUT:
+		var newData dto.Data
+		if err := json.Unmarshal([]byte(event.Data), &newData); err != nil {
+			return err
+		}
+		if newData.Flags == nil {
+			newData.Flags = map[string]dto.FlagDTO{}
+		}
+		if newData.Segments == nil {
+			newData.Segments = map[string]dto.SegmentDTO{}
+		}
+		newData.MatchType()
+		return ss.Poller.Update(func(current *dto.Data) (*dto.Data, error) {
+			return &newData, nil
+		})
+	case EVENT_PATCH, EVENT_DELETE:
+		var patch streamPatch
+		if err := json.Unmarshal([]byte(event.Data), &patch); err != nil {
+			return err
+		}
+		return ss.Poller.Update(func(current *dto.Data) (*dto.Data, error) {
+			return applyPatch(current, event.Name, patch)
+		})
+	default:
+		return fmt.Errorf("unknown stream event: %s", event.Name)
+	}
+}
+
+// applyPatch returns nil for a patch older than the current data, it may arrive after a put that already includes it
+func applyPatch(current *dto.Data, eventName string, patch streamPatch) (*dto.Data, error) {
+	if patch.LastUpdatedOn < current.LastUpdatedOn {
+		return nil, nil
+	}
+	flags := make(map[string]dto.FlagDTO, len(current.Flags))
+	for key, value := range current.Flags {
+		flags[key] = value
+	}
+	segments := make(map[string]dto.SegmentDTO, len(current.Segments))
+	for key, value := range current.Segments {
+		segments[key] = value
+	}
+
+	switch {
+	case strings.HasPrefix(patch.Path, FLAGS_PATH_PREFIX):
+		flagId := strings.TrimPrefix(patch.Path, FLAGS_PATH_PREFIX)
+		if eventName == EVENT_DELETE {
+			delete(flags, flagId)
+			break
+		}
+		var flag dto.FlagDTO
+		if err := json.Unmarshal(patch.Data, &flag); err != nil {
+			return nil, err
+		}
+		flags[flagId] = flag
+	case strings.HasPrefix(patch.Path, SEGMENTS_PATH_PREFIX):
+		segmentId := strings.TrimPrefix(patch.Path, SEGMENTS_PATH_PREFIX)
+		if eventName == EVENT_DELETE {
+			delete(segments, segmentId)
+			break
+		}
+		var segment dto.SegmentDTO
+		if err := json.Unmarshal(patch.Data, &segment); err != nil {
+			return nil, err
+		}
+		segment.MatchType()
+		segments[segmentId] = segment
+	default:
+		return nil, fmt.Errorf("unknown stream patch path: %s", patch.Path)
+	}
+
+	return &dto.Data{
+		Segments:      segments,
+		Flags:         flags,
+		LastUpdatedOn: patch.LastUpdatedOn,
+	}, nil
+}
+
+func (ss *StreamingServiceImpl) startFallback(ctx context.Context) {
+	ss.fallbackLock.Lock()
+	defer ss.fallbackLock.Unlock()
+	if ss.fallbackCancel != nil {
+		return
+	}
+	fallbackCtx, cancel := context.WithCancel(ctx)
+	ss.fallbackCancel = cancel
+	go ss.Poller.Start(fallbackCtx)
+}
+
+func (ss *StreamingServiceImpl) stopFallback() {
+	ss.fallbackLock.Lock()
+	defer ss.fallbackLock.Unlock()
+	if ss.fallbackCancel != nil {
+		ss.fallbackCancel()
+		ss.fallbackCancel = nil
+	}
+}
@@ -0,0 +1,188 @@
+package impl_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/flagsense/go-sdk/constants"
+	"github.com/flagsense/go-sdk/pkg/dto"
+	"github.com/flagsense/go-sdk/pkg/enums"
+	"github.com/flagsense/go-sdk/pkg/fstestdata"
+	"github.com/flagsense/go-sdk/pkg/model"
+	"github.com/flagsense/go-sdk/pkg/services"
+	"github.com/flagsense/go-sdk/pkg/services/impl"
+)
+
+// sseServer serves every stream connection from events, a connection ends when a disconnect is sent
+type sseServer struct {
+	*httptest.Server
+	events      chan string
+	disconnect  chan struct{}
+	connections chan struct{}
+	streamDown  bool
+	latest      *dto.Data
+}
+
+func newSSEServer(streamDown bool, latest *dto.Data) *sseServer {
+	s := &sseServer{
+		events:      make(chan string),
+		disconnect:  make(chan struct{}),
+		connections: make(chan struct{}, 10),
+		streamDown:  streamDown,
+		latest:      latest,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/stream", s.stream)
+	mux.HandleFunc("/fetchLatest", s.fetchLatest)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+func (s *sseServer) stream(w http.ResponseWriter, req *http.Request) {
+	if s.streamDown {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.WriteHeader(http.StatusOK)
+	w.(http.Flusher).Flush()
+	s.connections <- struct{}{}
+	for {
+		select {
+		case event := <-s.events:
+			fmt.Fprint(w, event)
+			w.(http.Flusher).Flush()
+		case <-s.disconnect:
+			return
+		case <-req.Context().Done():
+			return
+		}
+	}
+}
+
+func (s *sseServer) fetchLatest(w http.ResponseWriter, req *http.Request) {
+	if s.latest == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	json.NewEncoder(w).Encode(s.latest)
+}
+
+func (s *sseServer) send(t *testing.T, name string, data interface{}) {
+	t.Helper()
+	content, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case s.events <- fmt.Sprintf("event: %s\ndata: %s\n\n", name, content):
+	case <-time.After(5 * time.Second):
+		t.Fatalf("%s event not read", name)
+	}
+}
+
+func newStreamingService(server *sseServer) services.FlagsenseService {
+	captureEvents := false
+	return impl.NewFlagsenseServiceWithOptions("streaming", "secret", enums.NewEnvironment(constants.DEV), impl.Options{
+		Streaming:     true,
+		StreamingUrl:  server.URL + "/stream",
+		SDKServiceUrl: server.URL,
+		CaptureEvents: &captureEvents,
+	})
+}
+
+func stringFlag(value string) dto.FlagDTO {
+	return stringFlagWithId("flag", value)
+}
+
+func stringFlagWithId(flagId string, value string) dto.FlagDTO {
+	return fstestdata.New().Flag(flagId).StringFlag().Variations("a", "b", "c").FallthroughVariation(value).Build()
+}
+
+func expectValue(t *testing.T, service services.FlagsenseService, expected string) {
+	t.Helper()
+	expectFlagValue(t, service, "flag", expected)
+}
+
+func expectFlagValue(t *testing.T, service services.FlagsenseService, flagId string, expected string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	var value interface{}
+	for time.Now().Before(deadline) {
+		value = service.StringVariation(model.FSFlag{FlagId: flagId, DefaultValue: "default"}, model.FSUser{UserId: "user"}).Value
+		if value == expected {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("expected %s, got %v", expected, value)
+}
+
+func TestStreamingPutPatchDeleteAndReconnect(t *testing.T) {
+	server := newSSEServer(false, nil)
+	defer server.Close()
+	service := newStreamingService(server)
+	defer service.Close()
+
+	server.send(t, "put", dto.Data{
+		Flags:         map[string]dto.FlagDTO{"flag": stringFlag("a")},
+		Segments:      map[string]dto.SegmentDTO{},
+		LastUpdatedOn: 1,
+	})
+	initialized := make(chan struct{})
+	go func() {
+		service.WaitForInitializationComplete()
+		close(initialized)
+	}()
+	waitFor(t, "initialization", initialized)
+	expectValue(t, service, "a")
+
+	server.send(t, "patch", map[string]interface{}{"path": "/flags/flag", "data": stringFlag("b"), "lastUpdatedOn": 2})
+	expectValue(t, service, "b")
+
+	// a patch older than the data is ignored, the next patch shows that it was read
+	server.send(t, "patch", map[string]interface{}{"path": "/flags/flag", "data": stringFlag("c"), "lastUpdatedOn": 1})
+	server.send(t, "patch", map[string]interface{}{"path": "/flags/other", "data": stringFlagWithId("other", "a"), "lastUpdatedOn": 2})
+	expectFlagValue(t, service, "other", "a")
+	expectValue(t, service, "b")
+
+	server.send(t, "delete", map[string]interface{}{"path": "/flags/flag", "lastUpdatedOn": 3})
+	expectValue(t, service, "default")
+
+	<-server.connections
+	server.disconnect <- struct{}{}
+	select {
+	case <-server.connections:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream not reconnected")
+	}
+	server.send(t, "put", dto.Data{
+		Flags:         map[string]dto.FlagDTO{"flag": stringFlag("c")},
+		Segments:      map[string]dto.SegmentDTO{},
+		LastUpdatedOn: 4,
+	})
+	expectValue(t, service, "c")
+}
+
+func TestStreamingFallsBackToPolling(t *testing.T) {
+	server := newSSEServer(true, &dto.Data{
+		Flags:         map[string]dto.FlagDTO{"flag": stringFlag("b")},
+		Segments:      map[string]dto.SegmentDTO{},
+		LastUpdatedOn: 1,
+	})
+	defer server.Close()
+	service := newStreamingService(server)
+	defer service.Close()
+
+	initialized := make(chan struct{})
+	go func() {
+		service.WaitForInitializationComplete()
+		close(initialized)
+	}()
+	waitFor(t, "initialization", initialized)
+	expectValue(t, service, "b")
+}
@@ -4,96 +4,164 @@ import (
 	"errors"
 	"fmt"
 	"github.com/flagsense/go-sdk/pkg/dto"
+	"github.com/flagsense/go-sdk/pkg/model"
+	"github.com/flagsense/go-sdk/pkg/services"
 	"github.com/flagsense/go-sdk/pkg/util"
 	"github.com/hashicorp/go-version"
 	"github.com/teltech/logger"
 	"github.com/twmb/murmur3"
 	"math"
+	"reflect"
 	"strings"
+	"time"
 )
 
 const (
 	TOTAL_THREE_DECIMAL_TRAFFIC = 100000
+
+	// PRERELEASE_STRICT keeps the go-version semantics, a prerelease only satisfies a RANGE naming a prerelease of the same version
+	PRERELEASE_STRICT = "STRICT"
+	// PRERELEASE_CORE matches a prerelease as its release, 2.0.0-beta as 2.0.0
+	PRERELEASE_CORE = "CORE"
+	// PRERELEASE_EXCLUDE never matches a prerelease
+	PRERELEASE_EXCLUDE = "EXCLUDE"
 )
 
 var MAX_HASH_VALUE = math.Pow(2, 32)
 
 type UserVariantServiceImpl struct {
-	Data   *dto.Data
-	logger *logger.Log
+	Store services.FeatureStore
+	// Clock is the current time for relative DATETIME rules
+	Clock func() time.Time
+	// Prerelease is how VERSION rules treat prerelease attributes, PRERELEASE_STRICT by default
+	Prerelease string
+	logger     *logger.Log
 }
 
-func NewUserVariantService(data *dto.Data, logger *logger.Log) *UserVariantServiceImpl {
+func NewUserVariantService(store services.FeatureStore, logger *logger.Log) *UserVariantServiceImpl {
 	return &UserVariantServiceImpl{
-		Data:   data,
-		logger: logger,
+		Store:      store,
+		Clock:      time.Now,
+		Prerelease: PRERELEASE_STRICT,
+		logger:     logger,
 	}
 }
 
 func (uvs *UserVariantServiceImpl) GetUserVariant(userVariantDTO *dto.UserVariantDTO) error {
 	if strings.TrimSpace(userVariantDTO.UserId) == "" {
+		userVariantDTO.Reason = model.NewErrorReason(model.ERROR_USER_NOT_SPECIFIED)
 		return errors.New(fmt.Sprintf("Bad user: %s", userVariantDTO.UserId))
 	}
-	flagDTO := uvs.getFlagData(userVariantDTO.FlagId)
+	// a single snapshot is used for the whole evaluation
+	data := uvs.Store.All()
+	flagDTO := uvs.getFlagData(data, userVariantDTO.FlagId)
 
 	// assumption ID is always present for flag
 	if flagDTO.ID == "" {
+		userVariantDTO.Reason = model.NewErrorReason(model.ERROR_FLAG_NOT_FOUND)
 		return errors.New("Flag not found")
 	}
 
 	if flagDTO.Type != userVariantDTO.ExpectedVariantType {
+		userVariantDTO.Reason = model.NewErrorReason(model.ERROR_WRONG_TYPE)
 		return errors.New("Bad flag type specified")
 	}
 
-	userVariantKey := uvs.getUserVariantKey(*userVariantDTO, flagDTO)
+	userVariantKey, reason := uvs.getUserVariantKey(*userVariantDTO, flagDTO, uvs.getSegmentsMap(data))
 	userVariantDTO.Key = userVariantKey
+	userVariantDTO.Reason = reason
 	userVariantDTO.Value = flagDTO.Variants[userVariantKey].Value
 
 	return nil
 }
 
-func (uvs *UserVariantServiceImpl) getUserVariantKey(userVariantDTO dto.UserVariantDTO, flagDTO dto.FlagDTO) string {
+// evaluateWithoutEvents evaluates a flag as its own type, it is used where no variation was asked for by the caller
+func evaluateWithoutEvents(userVariantService services.UserVariantService, flagDTO dto.FlagDTO,
+	user model.FSUser) (userVariantDTO dto.UserVariantDTO, err error) {
+	defer func() {
+		if panicErr := recover(); panicErr != nil {
+			userVariantDTO.Reason = model.NewErrorReason(model.ERROR_EXCEPTION)
+			err = fmt.Errorf("panic in evaluation: %v", panicErr)
+		}
+	}()
+
+	userVariantDTO = dto.UserVariantDTO{
+		FlagId:              flagDTO.ID,
+		UserId:              user.UserId,
+		Attributes:          user.Attributes,
+		ExpectedVariantType: flagDTO.Type,
+	}
+	err = userVariantService.GetUserVariant(&userVariantDTO)
+	return userVariantDTO, err
+}
+
+func (uvs *UserVariantServiceImpl) getUserVariantKey(userVariantDTO dto.UserVariantDTO, flagDTO dto.FlagDTO,
+	segments map[string]dto.SegmentDTO) (string, model.FSEvaluationReason) {
 	userId := userVariantDTO.UserId
 	attributes := userVariantDTO.Attributes
 
 	envData := flagDTO.EnvData
 	if envData.Status == dto.INACTIVE {
-		return envData.OffVariant
+		return envData.OffVariant, model.NewReason(model.REASON_OFF)
 	}
 
-	segments := uvs.getSegmentsMap()
 	if !uvs.matchesPrerequisites(userId, attributes, envData.PreRequisites, segments) {
-		return envData.OffVariant
+		return envData.OffVariant, model.NewReason(model.REASON_PREREQUISITE_FAILED)
 	}
 
 	targetUsers := envData.TargetUsers
 	if targetUsers != nil && targetUsers[userId] != "" {
-		return targetUsers[userId]
+		return targetUsers[userId], model.NewReason(model.REASON_TARGET_MATCH)
 	}
 
 	targetSegmentsOrder := envData.TargetSegmentsOrder
 	if targetSegmentsOrder != nil {
 		for _, targetSegment := range targetSegmentsOrder {
 			if uvs.isUserInSegment(userId, attributes, segments[targetSegment]) {
-				return uvs.allocateTrafficVariant(userId, flagDTO, envData.TargetSegments[targetSegment])
+				reason := model.NewSegmentMatchReason(targetSegment)
+				return uvs.allocateTrafficVariant(userId, attributes, flagDTO, envData.TargetSegments[targetSegment], &reason), reason
 			}
 		}
 	}
-	return uvs.allocateTrafficVariant(userId, flagDTO, envData.Traffic)
+	reason := model.NewReason(model.REASON_FALLTHROUGH)
+	return uvs.allocateTrafficVariant(userId, attributes, flagDTO, envData.Traffic, &reason), reason
 }
 
-func (uvs *UserVariantServiceImpl) getFlagData(flagId string) dto.FlagDTO {
-	if uvs.Data.Flags == nil || len(uvs.Data.Flags) == 0 {
+// getBucketingId returns the value of the flag's BucketBy attribute and its key, or the userId and an empty key when
+// the flag has none or the user lacks it. Only strings, numbers and booleans can be bucketed by.
+func (uvs *UserVariantServiceImpl) getBucketingId(userId string, attributes map[string]interface{}, flagDTO dto.FlagDTO) (string, string) {
+	if strings.TrimSpace(flagDTO.BucketBy) == "" {
+		return userId, ""
+	}
+	var bucketingId string
+	switch value := uvs.getAttributeValue(userId, attributes, flagDTO.BucketBy).(type) {
+	case string:
+		bucketingId = value
+	case bool:
+		bucketingId = fmt.Sprint(value)
+	default:
+		if util.IsNumber(value) {
+			bucketingId = fmt.Sprint(value)
+		}
+	}
+	if bucketingId == "" {
+		return userId, ""
+	}
+	return bucketingId, flagDTO.BucketBy
+}
+
+func (uvs *UserVariantServiceImpl) getFlagData(data *dto.Data, flagId string) dto.FlagDTO {
+	if data.Flags == nil || len(data.Flags) == 0 {
 		return dto.FlagDTO{}
 	}
-	return uvs.Data.Flags[flagId]
+	return data.Flags[flagId]
 }
 
-func (uvs *UserVariantServiceImpl) getSegmentsMap() map[string]dto.SegmentDTO {
-	if uvs.Data.Segments == nil || len(uvs.Data.Segments) == 0 {
+func (uvs *UserVariantServiceImpl) getSegmentsMap(data *dto.Data) map[string]dto.SegmentDTO {
+	if data.Segments == nil || len(data.Segments) == 0 {
 		return map[string]dto.SegmentDTO{}
 	}
-	return uvs.Data.Segments
+	return data.Segments
 }
 
 func (uvs *UserVariantServiceImpl) matchesPrerequisites(userId string, attributes map[string]interface{},
@@ -118,15 +186,20 @@ func (uvs *UserVariantServiceImpl) isUserInSegment(userId string, attributes map
 	}
 
 	for _, rule := range segmentDTO.Rules {
-		if !uvs.matchesAndRule(userId, attributes, rule) {
+		if !uvs.matchesAndRule(userId, attributes, segmentDTO.ID, rule) {
 			return false
 		}
 	}
 	return true
 }
 
-func (uvs *UserVariantServiceImpl) matchesAndRule(userId string, attributes map[string]interface{}, orRules []*dto.RulesDTO) bool {
+func (uvs *UserVariantServiceImpl) matchesAndRule(userId string, attributes map[string]interface{}, segmentId string, orRules []*dto.RulesDTO) bool {
 	for _, orRule := range orRules {
+		// a malformed rule never matches, also when negated, the other rules are still evaluated
+		if orRule.Err != nil {
+			uvs.logger.Errorf("skipping malformed rule of segment:%s, error:%+v", segmentId, orRule.Err)
+			continue
+		}
 		if uvs.matchesRule(userId, attributes, orRule) {
 			return true
 		}
@@ -139,45 +212,149 @@ func (uvs *UserVariantServiceImpl) matchesRule(userId string, attributes map[str
 	if attributeValue == nil {
 		return false
 	}
+
+	var userMatchesRule, ok bool
+	elements, isList := listElements(attributeValue)
+	switch {
+	case rule.Operator == dto.CONTAINS_ANY || rule.Operator == dto.CONTAINS_ALL:
+		if !isList {
+			elements = []interface{}{attributeValue}
+		}
+		userMatchesRule, ok = uvs.matchesContainsRule(rule, elements)
+	case isList:
+		userMatchesRule, ok = uvs.matchesElements(rule, elements)
+	default:
+		userMatchesRule, ok = uvs.matchesValue(rule, attributeValue)
+	}
+	// a value of the wrong type never matches, even for a negated rule
+	if !ok {
+		return false
+	}
+	return userMatchesRule == rule.Match
+}
+
+// listElements returns the elements of a slice or array attribute
+func listElements(attributeValue interface{}) ([]interface{}, bool) {
+	if list, ok := attributeValue.([]interface{}); ok {
+		return list, true
+	}
+	value := reflect.ValueOf(attributeValue)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return nil, false
+	}
+	elements := make([]interface{}, value.Len())
+	for i := range elements {
+		elements[i] = value.Index(i).Interface()
+	}
+	return elements, true
+}
+
+// matchesElements matches a list attribute when any element matches the rule, or every element for a MatchAll rule.
+// Elements of the wrong type do not match, the list is of the wrong type only if none of them has the rule type.
+func (uvs *UserVariantServiceImpl) matchesElements(rule *dto.RulesDTO, elements []interface{}) (bool, bool) {
+	anyTyped := false
+	for _, element := range elements {
+		matches, ok := uvs.matchesValue(rule, element)
+		anyTyped = anyTyped || ok
+		if rule.MatchAll && !(ok && matches) {
+			return false, anyTyped
+		}
+		if !rule.MatchAll && ok && matches {
+			return true, true
+		}
+	}
+	return rule.MatchAll && len(elements) > 0, anyTyped
+}
+
+// matchesContainsRule checks the rule values against the elements, CONTAINS_ANY needs one of them and CONTAINS_ALL every one
+func (uvs *UserVariantServiceImpl) matchesContainsRule(rule *dto.RulesDTO, elements []interface{}) (bool, bool) {
+	anyTyped := false
+	for i, value := range rule.Values {
+		equalsRule := *rule
+		equalsRule.Operator = dto.EQ
+		equalsRule.Values = []interface{}{value}
+		if i < len(rule.Versions) {
+			equalsRule.Versions = rule.Versions[i : i+1]
+		}
+
+		found := false
+		for _, element := range elements {
+			matches, ok := uvs.matchesValue(&equalsRule, element)
+			anyTyped = anyTyped || ok
+			if ok && matches {
+				found = true
+				break
+			}
+		}
+		if found && rule.Operator == dto.CONTAINS_ANY {
+			return true, true
+		}
+		if !found && rule.Operator == dto.CONTAINS_ALL {
+			return false, anyTyped
+		}
+	}
+	return rule.Operator == dto.CONTAINS_ALL && len(rule.Values) > 0, anyTyped
+}
+
+// matchesValue matches a single attribute value, ok is false when the value does not have the rule type
+func (uvs *UserVariantServiceImpl) matchesValue(rule *dto.RulesDTO, attributeValue interface{}) (matches bool, ok bool) {
 	attributeValueType := fmt.Sprintf("%T", attributeValue)
 	var userMatchesRule bool
 
 	switch rule.Type {
-	case dto.INT:
-		if !strings.Contains(attributeValueType, "int") && !strings.Contains(attributeValueType, "float") {
-			return false
+	case dto.INT, dto.INT32:
+		value, err := util.ToInt32(attributeValue)
+		if err != nil {
+			return false, false
+		}
+		userMatchesRule = uvs.matchesInt32Rule(rule, value)
+		break
+	case dto.INT64:
+		value, err := util.ToInt64(attributeValue)
+		if err != nil {
+			return false, false
 		}
-		userMatchesRule = uvs.matchesInt32Rule(rule, util.ConvertToInt32(attributeValue))
+		userMatchesRule = uvs.matchesInt64Rule(rule, value)
 		break
 	case dto.BOOL:
 		if attributeValueType != "bool" {
-			return false
+			return false, false
 		}
 		userMatchesRule = uvs.matchesBoolRule(rule, attributeValue.(bool))
 		break
 	case dto.DOUBLE:
-		if !strings.Contains(attributeValueType, "float") && !strings.Contains(attributeValueType, "int") {
-			return false
+		if !util.IsNumber(attributeValue) {
+			return false, false
 		}
 		userMatchesRule = uvs.matchesFloat64Rule(rule, util.ConvertToFloat64(attributeValue))
 		break
 	case dto.STRING:
 		if attributeValueType != "string" {
-			return false
+			return false, false
 		}
 		userMatchesRule = uvs.matchesStringRule(rule, attributeValue.(string))
 		break
 	case dto.VERSION:
 		if attributeValueType != "string" {
-			return false
+			return false, false
 		}
-		userMatchesRule = uvs.matchesVersionRule(rule, attributeValue.(string))
+		attrVersion, ok := uvs.attributeVersion(attributeValue.(string))
+		if !ok {
+			return false, false
+		}
+		userMatchesRule = uvs.matchesVersionRule(rule, attrVersion)
+		break
+	case dto.DATETIME:
+		value, err := util.ToTime(attributeValue)
+		if err != nil {
+			return false, false
+		}
+		userMatchesRule = uvs.matchesTimeRule(rule, value)
 		break
 	default:
 		userMatchesRule = false
 	}
-
-	return userMatchesRule == rule.Match
+	return userMatchesRule, true
 }
 
 func (uvs *UserVariantServiceImpl) getAttributeValue(userId string, attributes map[string]interface{}, key string) interface{} {
@@ -185,6 +362,12 @@ func (uvs *UserVariantServiceImpl) getAttributeValue(userId string, attributes m
 	if attributesContainsKey {
 		return val
 	}
+	// a flat attribute wins over a nested one with the same path, so existing keys with dots keep working
+	if path := util.SplitPath(key); path != nil {
+		if val, found := util.GetPath(attributes, path); found {
+			return val
+		}
+	}
 	if key == "id" {
 		return userId
 	}
@@ -193,20 +376,28 @@ func (uvs *UserVariantServiceImpl) getAttributeValue(userId string, attributes m
 
 func (uvs *UserVariantServiceImpl) matchesFloat64Rule(rule *dto.RulesDTO, attributeValue float64) bool {
 	values := rule.Values
+	if rule.Operator == dto.IOF {
+		return util.Contains(values, attributeValue)
+	}
+	if rule.Operator == dto.NIOF {
+		return !util.Contains(values, attributeValue)
+	}
+	value, ok := values[0].(float64)
+	if !ok {
+		return false
+	}
 
 	switch rule.Operator {
 	case dto.LT:
-		return attributeValue < values[0].(float64)
+		return attributeValue < value
 	case dto.LTE:
-		return attributeValue <= values[0].(float64)
+		return attributeValue <= value
 	case dto.EQ:
-		return attributeValue == values[0].(float64)
+		return attributeValue == value
 	case dto.GT:
-		return attributeValue > values[0].(float64)
+		return attributeValue > value
 	case dto.GTE:
-		return attributeValue >= values[0].(float64)
-	case dto.IOF:
-		return util.Contains(values, attributeValue)
+		return attributeValue >= value
 	default:
 		return false
 	}
@@ -214,20 +405,58 @@ func (uvs *UserVariantServiceImpl) matchesFloat64Rule(rule *dto.RulesDTO, attrib
 
 func (uvs *UserVariantServiceImpl) matchesInt32Rule(rule *dto.RulesDTO, attributeValue int32) bool {
 	values := rule.Values
+	if rule.Operator == dto.IOF {
+		return util.Contains(values, attributeValue)
+	}
+	if rule.Operator == dto.NIOF {
+		return !util.Contains(values, attributeValue)
+	}
+	// rules with values MatchType could not convert fail before matching, the check only guards against a panic
+	value, ok := values[0].(int32)
+	if !ok {
+		return false
+	}
 
 	switch rule.Operator {
 	case dto.LT:
-		return attributeValue < values[0].(int32)
+		return attributeValue < value
 	case dto.LTE:
-		return attributeValue <= values[0].(int32)
+		return attributeValue <= value
 	case dto.EQ:
-		return attributeValue == values[0].(int32)
+		return attributeValue == value
 	case dto.GT:
-		return attributeValue > values[0].(int32)
+		return attributeValue > value
 	case dto.GTE:
-		return attributeValue >= values[0].(int32)
-	case dto.IOF:
+		return attributeValue >= value
+	default:
+		return false
+	}
+}
+
+func (uvs *UserVariantServiceImpl) matchesInt64Rule(rule *dto.RulesDTO, attributeValue int64) bool {
+	values := rule.Values
+	if rule.Operator == dto.IOF {
 		return util.Contains(values, attributeValue)
+	}
+	if rule.Operator == dto.NIOF {
+		return !util.Contains(values, attributeValue)
+	}
+	value, ok := values[0].(int64)
+	if !ok {
+		return false
+	}
+
+	switch rule.Operator {
+	case dto.LT:
+		return attributeValue < value
+	case dto.LTE:
+		return attributeValue <= value
+	case dto.EQ:
+		return attributeValue == value
+	case dto.GT:
+		return attributeValue > value
+	case dto.GTE:
+		return attributeValue >= value
 	default:
 		return false
 	}
@@ -246,6 +475,27 @@ func (uvs *UserVariantServiceImpl) matchesStringRule(rule *dto.RulesDTO, attribu
 		return strings.HasSuffix(attributeValue, values[0].(string))
 	case dto.IOF:
 		return util.Contains(values, attributeValue)
+	case dto.NIOF:
+		return !util.Contains(values, attributeValue)
+	case dto.EQ_CI:
+		return strings.EqualFold(attributeValue, values[0].(string))
+	case dto.HAS_CI:
+		return strings.Contains(strings.ToLower(attributeValue), strings.ToLower(values[0].(string)))
+	case dto.SW_CI:
+		return strings.HasPrefix(strings.ToLower(attributeValue), strings.ToLower(values[0].(string)))
+	case dto.EW_CI:
+		return strings.HasSuffix(strings.ToLower(attributeValue), strings.ToLower(values[0].(string)))
+	case dto.IOF_CI:
+		return util.ContainsFold(values, attributeValue)
+	case dto.NIOF_CI:
+		return !util.ContainsFold(values, attributeValue)
+	case dto.MATCHES, dto.MATCHES_CI:
+		for _, pattern := range rule.Patterns {
+			if pattern.MatchString(attributeValue) {
+				return true
+			}
+		}
+		return false
 	default:
 		return false
 	}
@@ -260,40 +510,95 @@ func (uvs *UserVariantServiceImpl) matchesBoolRule(rule *dto.RulesDTO, attribute
 	return false
 }
 
-func (uvs *UserVariantServiceImpl) matchesVersionRule(rule *dto.RulesDTO, attributeValue string) bool {
-	values := rule.Values
-	attrVersion, err := version.NewVersion(attributeValue)
-	if err != nil {
-		attrVersion, _ = version.NewVersion("0.0")
-	}
-	valueVersion, _ := version.NewVersion(values[0].(string))
-
+func (uvs *UserVariantServiceImpl) matchesVersionRule(rule *dto.RulesDTO, attrVersion *version.Version) bool {
 	switch rule.Operator {
 	case dto.LT:
-		return attrVersion.Compare(valueVersion) < 0
+		return attrVersion.Compare(rule.Versions[0]) < 0
 	case dto.LTE:
-		return attrVersion.Compare(valueVersion) <= 0
+		return attrVersion.Compare(rule.Versions[0]) <= 0
 	case dto.EQ:
-		return attrVersion.Compare(valueVersion) == 0
+		return attrVersion.Compare(rule.Versions[0]) == 0
 	case dto.GT:
-		return attrVersion.Compare(valueVersion) > 0
+		return attrVersion.Compare(rule.Versions[0]) > 0
 	case dto.GTE:
-		return attrVersion.Compare(valueVersion) >= 0
+		return attrVersion.Compare(rule.Versions[0]) >= 0
 	case dto.IOF:
-		return util.ContainsVersion(values, attrVersion)
+		return containsVersion(rule.Versions, attrVersion)
+	case dto.NIOF:
+		return !containsVersion(rule.Versions, attrVersion)
+	case dto.RANGE:
+		for _, constraints := range rule.Constraints {
+			if constraints.Check(attrVersion) {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+func containsVersion(versions []*version.Version, search *version.Version) bool {
+	for _, value := range versions {
+		if value.Equal(search) {
+			return true
+		}
+	}
+	return false
+}
+
+// attributeVersion parses a version attribute, invalid versions and prereleases excluded by the Prerelease mode are not ok
+func (uvs *UserVariantServiceImpl) attributeVersion(attributeValue string) (*version.Version, bool) {
+	attrVersion, err := version.NewVersion(attributeValue)
+	if err != nil {
+		return nil, false
+	}
+	if attrVersion.Prerelease() == "" {
+		return attrVersion, true
+	}
+	switch uvs.Prerelease {
+	case PRERELEASE_CORE:
+		return attrVersion.Core(), true
+	case PRERELEASE_EXCLUDE:
+		return nil, false
+	default:
+		return attrVersion, true
+	}
+}
+
+func (uvs *UserVariantServiceImpl) matchesTimeRule(rule *dto.RulesDTO, attributeValue time.Time) bool {
+	values := rule.Values
+
+	switch rule.Operator {
+	case dto.EQ:
+		return attributeValue.Equal(values[0].(time.Time))
+	case dto.BEFORE:
+		return attributeValue.Before(values[0].(time.Time))
+	case dto.AFTER:
+		return attributeValue.After(values[0].(time.Time))
+	case dto.BETWEEN:
+		return !attributeValue.Before(values[0].(time.Time)) && !attributeValue.After(values[1].(time.Time))
+	case dto.WITHIN_LAST_DAYS:
+		now := uvs.Clock()
+		from := now.Add(-time.Duration(values[0].(float64) * float64(24*time.Hour)))
+		return !attributeValue.Before(from) && !attributeValue.After(now)
 	default:
 		return false
 	}
 }
 
-func (uvs *UserVariantServiceImpl) allocateTrafficVariant(userId string, flagDTO dto.FlagDTO, traffic map[string]int) string {
+// allocateTrafficVariant sets the attribute bucketed by on the reason, when the traffic is split between variants
+func (uvs *UserVariantServiceImpl) allocateTrafficVariant(userId string, attributes map[string]interface{}, flagDTO dto.FlagDTO,
+	traffic map[string]int, reason *model.FSEvaluationReason) string {
 	if len(traffic) == 1 {
 		for key, _ := range traffic {
 			return key
 		}
 	}
 
-	bucketingId := userId + flagDTO.ID
+	bucketingId, bucketBy := uvs.getBucketingId(userId, attributes, flagDTO)
+	reason.BucketBy = bucketBy
+	bucketingId = bucketingId + flagDTO.ID
 	variantsOrder := flagDTO.VariantsOrder
 
 	hasher := murmur3.SeedNew32(flagDTO.Seed)
@@ -0,0 +1,346 @@
+package impl_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/flagsense/go-sdk/constants"
+	"github.com/flagsense/go-sdk/pkg/dto"
+	"github.com/flagsense/go-sdk/pkg/enums"
+	"github.com/flagsense/go-sdk/pkg/fstestdata"
+	"github.com/flagsense/go-sdk/pkg/model"
+	"github.com/flagsense/go-sdk/pkg/services"
+	"github.com/flagsense/go-sdk/pkg/services/impl"
+)
+
+const SEGMENT_ID = "segment"
+
+type ruleTest struct {
+	name       string
+	segment    *fstestdata.SegmentBuilder
+	attributes map[string]interface{}
+	expected   string
+}
+
+func segment() *fstestdata.SegmentBuilder {
+	return fstestdata.New().Segment(SEGMENT_ID)
+}
+
+func newTestDataService(t *testing.T, td *fstestdata.TestData, options impl.Options) services.FlagsenseService {
+	captureEvents := false
+	options.CaptureEvents = &captureEvents
+	options.DataSourceFactory = td
+	service := impl.NewFlagsenseServiceWithOptions("rules", "secret", enums.NewEnvironment(constants.DEV), options)
+	t.Cleanup(service.Close)
+	return service
+}
+
+// evaluateSegment serves "in" to users of the segment and "out" to everyone else
+func evaluateSegment(t *testing.T, segment *fstestdata.SegmentBuilder, attributes map[string]interface{}) model.FSEvaluationDetail {
+	return evaluateSegmentWithOptions(t, impl.Options{}, segment, attributes)
+}
+
+func evaluateSegmentWithOptions(t *testing.T, options impl.Options, segment *fstestdata.SegmentBuilder,
+	attributes map[string]interface{}) model.FSEvaluationDetail {
+	td := fstestdata.New()
+	td.UpdateSegment(segment)
+	td.Update(td.Flag("flag").StringFlag().Variations("out", "in").FallthroughVariation("out").VariationForSegment(SEGMENT_ID, "in"))
+	service := newTestDataService(t, td, options)
+	return service.StringVariationDetail(model.FSFlag{FlagId: "flag", DefaultValue: "default"},
+		model.FSUser{UserId: "user", Attributes: attributes})
+}
+
+func runRuleTests(t *testing.T, tests []ruleTest) {
+	runRuleTestsWithOptions(t, impl.Options{}, tests)
+}
+
+func runRuleTestsWithOptions(t *testing.T, options impl.Options, tests []ruleTest) {
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			detail := evaluateSegmentWithOptions(t, options, test.segment, test.attributes)
+			if detail.Value != test.expected {
+				t.Fatalf("expected %s, got %v %+v", test.expected, detail.Value, detail.Reason)
+			}
+		})
+	}
+}
+
+func TestInt64Rules(t *testing.T) {
+	runRuleTests(t, []ruleTest{
+		{"int64 above int32", segment().Rule("count", dto.INT64, dto.GT, int64(2999999999)),
+			map[string]interface{}{"count": int64(3000000000)}, "in"},
+		{"int64 below rule", segment().Rule("count", dto.INT64, dto.GT, int64(3000000000)),
+			map[string]interface{}{"count": int64(2999999999)}, "out"},
+		{"int64 beyond float precision", segment().Rule("count", dto.INT64, dto.EQ, int64(9007199254740993)),
+			map[string]interface{}{"count": int64(9007199254740993)}, "in"},
+		{"int64 next to the value", segment().Rule("count", dto.INT64, dto.EQ, int64(9007199254740993)),
+			map[string]interface{}{"count": int64(9007199254740992)}, "out"},
+		{"int64 in list", segment().Rule("count", dto.INT64, dto.IOF, int64(1), int64(3000000000)),
+			map[string]interface{}{"count": 3000000000}, "in"},
+		{"int attribute overflowing", segment().Rule("count", dto.INT, dto.GT, 5),
+			map[string]interface{}{"count": int64(5000000000)}, "out"},
+	})
+}
+
+func TestOverflowingIntRuleNeverMatches(t *testing.T) {
+	count := map[string]interface{}{"count": int64(3000000000)}
+	runRuleTests(t, []ruleTest{
+		{"overflowing value", segment().Rule("count", dto.INT, dto.EQ, int64(3000000000)), count, "out"},
+		{"negated overflowing value", segment().NotRule("count", dto.INT, dto.EQ, int64(3000000000)), count, "out"},
+		{"other rules still evaluated", segment().Rule("count", dto.INT, dto.EQ, int64(3000000000)).
+			OrRule("count", dto.INT64, dto.EQ, int64(3000000000)), count, "in"},
+	})
+}
+
+func TestInt64Variation(t *testing.T) {
+	td := fstestdata.New()
+	td.Update(td.Flag("flag").IntegerFlag().Variations(int64(3000000000)).FallthroughVariation(int64(3000000000)))
+	service := newTestDataService(t, td, impl.Options{})
+	fsFlag := model.FSFlag{FlagId: "flag", DefaultValue: int32(1)}
+	user := model.FSUser{UserId: "user"}
+
+	if detail := service.Int64VariationDetail(fsFlag, user); detail.Value != int64(3000000000) {
+		t.Fatalf("expected the int64 value, got %v %+v", detail.Value, detail.Reason)
+	}
+	detail := service.IntegerVariationDetail(fsFlag, user)
+	if detail.Value != int32(1) || detail.Reason != model.NewErrorReason(model.ERROR_WRONG_TYPE) {
+		t.Fatalf("expected the default for an overflowing int32, got %v %+v", detail.Value, detail.Reason)
+	}
+}
+
+func TestStringRules(t *testing.T) {
+	email := map[string]interface{}{"email": "Jane.Doe@Example.com"}
+	runRuleTests(t, []ruleTest{
+		{"matches", segment().Rule("email", dto.STRING, dto.MATCHES, `^\w+\.\w+@`), email, "in"},
+		{"matches any pattern", segment().Rule("email", dto.STRING, dto.MATCHES, `^admin@`, `@Example\.com$`), email, "in"},
+		{"matches is case sensitive", segment().Rule("email", dto.STRING, dto.MATCHES, `@example\.com$`), email, "out"},
+		{"matches ignoring case", segment().Rule("email", dto.STRING, dto.MATCHES_CI, `@example\.com$`), email, "in"},
+		{"not matches", segment().NotRule("email", dto.STRING, dto.MATCHES, `@example\.com$`), email, "in"},
+		{"not matches a matching pattern", segment().NotRule("email", dto.STRING, dto.MATCHES_CI, `@example\.com$`), email, "out"},
+		{"equals ignoring case", segment().Rule("email", dto.STRING, dto.EQ_CI, "jane.doe@example.COM"), email, "in"},
+		{"has ignoring case", segment().Rule("email", dto.STRING, dto.HAS_CI, "DOE"), email, "in"},
+		{"starts with ignoring case", segment().Rule("email", dto.STRING, dto.SW_CI, "JANE"), email, "in"},
+		{"ends with ignoring case", segment().Rule("email", dto.STRING, dto.EW_CI, "EXAMPLE.COM"), email, "in"},
+		{"ends with is case sensitive", segment().Rule("email", dto.STRING, dto.EW, "example.com"), email, "out"},
+		{"in list ignoring case", segment().Rule("email", dto.STRING, dto.IOF_CI, "john@example.com", "jane.doe@example.com"), email, "in"},
+		{"not in list", segment().Rule("email", dto.STRING, dto.NIOF, "jane.doe@example.com"), email, "in"},
+		{"not in list ignoring case", segment().Rule("email", dto.STRING, dto.NIOF_CI, "jane.doe@example.com"), email, "out"},
+		{"not in int list", segment().Rule("count", dto.INT, dto.NIOF, 1, 2), map[string]interface{}{"count": 3}, "in"},
+	})
+}
+
+func TestInvalidPatternNeverMatches(t *testing.T) {
+	email := map[string]interface{}{"email": "jane@example.com"}
+	runRuleTests(t, []ruleTest{
+		{"invalid pattern", segment().Rule("email", dto.STRING, dto.MATCHES, `(unclosed`), email, "out"},
+		{"negated invalid pattern", segment().NotRule("email", dto.STRING, dto.MATCHES, `(unclosed`), email, "out"},
+		{"other rules still evaluated", segment().Rule("email", dto.STRING, dto.MATCHES, `(unclosed`).
+			OrRule("email", dto.STRING, dto.EW, "@example.com"), email, "in"},
+	})
+}
+
+func TestDateTimeRules(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	signedUp := func(value interface{}) map[string]interface{} {
+		return map[string]interface{}{"signedUp": value}
+	}
+
+	runRuleTestsWithOptions(t, impl.Options{Clock: clock}, []ruleTest{
+		{"before", segment().Rule("signedUp", dto.DATETIME, dto.BEFORE, "2024-01-01T00:00:00Z"),
+			signedUp("2023-12-31T23:59:59Z"), "in"},
+		{"not before", segment().Rule("signedUp", dto.DATETIME, dto.BEFORE, "2024-01-01T00:00:00Z"),
+			signedUp("2024-01-01T00:00:00Z"), "out"},
+		{"after with offset", segment().Rule("signedUp", dto.DATETIME, dto.AFTER, "2024-01-01T00:00:00Z"),
+			signedUp("2024-01-01T00:30:00+01:00"), "out"},
+		{"after", segment().Rule("signedUp", dto.DATETIME, dto.AFTER, "2024-01-01T00:00:00Z"),
+			signedUp("2024-01-01T02:00:00+01:00"), "in"},
+		{"between", segment().Rule("signedUp", dto.DATETIME, dto.BETWEEN, "2024-01-01T00:00:00Z", "2024-02-01T00:00:00Z"),
+			signedUp("2024-01-15T00:00:00Z"), "in"},
+		{"between includes the bounds", segment().Rule("signedUp", dto.DATETIME, dto.BETWEEN, "2024-01-01T00:00:00Z", "2024-02-01T00:00:00Z"),
+			signedUp("2024-02-01T00:00:00Z"), "in"},
+		{"outside between", segment().Rule("signedUp", dto.DATETIME, dto.BETWEEN, "2024-01-01T00:00:00Z", "2024-02-01T00:00:00Z"),
+			signedUp("2024-02-01T00:00:01Z"), "out"},
+		{"unix seconds", segment().Rule("signedUp", dto.DATETIME, dto.BEFORE, 1704067200),
+			signedUp(int64(1704067199)), "in"},
+		{"unix millis", segment().Rule("signedUp", dto.DATETIME, dto.AFTER, "2024-01-01T00:00:00Z"),
+			signedUp(float64(1704067201000)), "in"},
+		{"time attribute", segment().Rule("signedUp", dto.DATETIME, dto.BEFORE, "2024-01-01T00:00:00Z"),
+			signedUp(time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC)), "in"},
+		{"within last days", segment().Rule("signedUp", dto.DATETIME, dto.WITHIN_LAST_DAYS, 7),
+			signedUp(now.Add(-6 * 24 * time.Hour)), "in"},
+		{"before last days", segment().Rule("signedUp", dto.DATETIME, dto.WITHIN_LAST_DAYS, 7),
+			signedUp(now.Add(-8 * 24 * time.Hour)), "out"},
+		{"in the future", segment().Rule("signedUp", dto.DATETIME, dto.WITHIN_LAST_DAYS, 7),
+			signedUp(now.Add(time.Hour)), "out"},
+		{"invalid attribute", segment().Rule("signedUp", dto.DATETIME, dto.BEFORE, "2024-01-01T00:00:00Z"),
+			signedUp("yesterday"), "out"},
+	})
+}
+
+func TestInvalidDateTimeNeverMatches(t *testing.T) {
+	signedUp := map[string]interface{}{"signedUp": "2023-01-01T00:00:00Z"}
+	runRuleTests(t, []ruleTest{
+		{"invalid time", segment().Rule("signedUp", dto.DATETIME, dto.BEFORE, "2024-01-01"), signedUp, "out"},
+		{"missing bound", segment().Rule("signedUp", dto.DATETIME, dto.BETWEEN, "2024-01-01T00:00:00Z"), signedUp, "out"},
+		{"days not number", segment().Rule("signedUp", dto.DATETIME, dto.WITHIN_LAST_DAYS, "7"), signedUp, "out"},
+		{"negated invalid time", segment().NotRule("signedUp", dto.DATETIME, dto.AFTER, "2024-01-01"), signedUp, "out"},
+		{"other rules still evaluated", segment().Rule("signedUp", dto.DATETIME, dto.BEFORE, "2024-01-01").
+			OrRule("signedUp", dto.DATETIME, dto.BEFORE, "2024-01-01T00:00:00Z"), signedUp, "in"},
+	})
+}
+
+type device struct {
+	OS      string  `json:"os"`
+	Version *string `json:"version"`
+}
+
+func TestNestedAttributeRules(t *testing.T) {
+	osVersion := "17.1"
+	attributes := map[string]interface{}{
+		"org":     map[string]interface{}{"plan": "enterprise", "seats": 250},
+		"devices": []interface{}{map[string]interface{}{"os": "ios"}, device{OS: "android"}},
+		"primary": &device{OS: "ios", Version: &osVersion},
+		"a/b":     map[string]interface{}{"c": "escaped"},
+	}
+	withFlatKey := map[string]interface{}{
+		"org.plan": "free",
+		"org":      map[string]interface{}{"plan": "enterprise"},
+	}
+
+	runRuleTests(t, []ruleTest{
+		{"dotted path", segment().Rule("org.plan", dto.STRING, dto.EQ, "enterprise"), attributes, "in"},
+		{"dotted path to a number", segment().Rule("org.seats", dto.INT, dto.GTE, 100), attributes, "in"},
+		{"json pointer", segment().Rule("/org/plan", dto.STRING, dto.EQ, "enterprise"), attributes, "in"},
+		{"json pointer with index", segment().Rule("/devices/0/os", dto.STRING, dto.EQ, "ios"), attributes, "in"},
+		{"json pointer into a struct", segment().Rule("/devices/1/os", dto.STRING, dto.EQ, "android"), attributes, "in"},
+		{"pointer field of a struct", segment().Rule("primary.version", dto.VERSION, dto.GTE, "17.0"), attributes, "in"},
+		{"escaped json pointer", segment().Rule("/a~1b/c", dto.STRING, dto.EQ, "escaped"), attributes, "in"},
+		{"flat dotted key wins", segment().Rule("org.plan", dto.STRING, dto.EQ, "free"), withFlatKey, "in"},
+		{"missing path", segment().Rule("org.owner", dto.STRING, dto.EQ, "enterprise"), attributes, "out"},
+		{"index out of range", segment().Rule("/devices/2/os", dto.STRING, dto.EQ, "ios"), attributes, "out"},
+		{"path through a string", segment().Rule("org.plan.name", dto.STRING, dto.EQ, "enterprise"), attributes, "out"},
+	})
+}
+
+func TestListAttributeRules(t *testing.T) {
+	attributes := map[string]interface{}{
+		"roles":    []string{"admin", "editor"},
+		"groups":   []interface{}{"beta", 42},
+		"ids":      []int64{7, 3000000000},
+		"versions": [2]string{"1.2.0", "2.0.1"},
+		"role":     "admin",
+	}
+
+	runRuleTests(t, []ruleTest{
+		{"any element", segment().Rule("roles", dto.STRING, dto.EQ, "editor"), attributes, "in"},
+		{"no element", segment().Rule("roles", dto.STRING, dto.EQ, "viewer"), attributes, "out"},
+		{"all elements", segment().Rule("roles", dto.STRING, dto.IOF, "admin", "editor", "viewer").MatchAll(), attributes, "in"},
+		{"not all elements", segment().Rule("roles", dto.STRING, dto.SW, "ad").MatchAll(), attributes, "out"},
+		{"negated any element", segment().NotRule("roles", dto.STRING, dto.EQ, "viewer"), attributes, "in"},
+		{"mixed types", segment().Rule("groups", dto.INT, dto.EQ, 42), attributes, "in"},
+		{"no element of the type", segment().NotRule("roles", dto.INT, dto.EQ, 42), attributes, "out"},
+		{"array of versions", segment().Rule("versions", dto.VERSION, dto.GTE, "2.0.0"), attributes, "in"},
+		{"contains any", segment().Rule("roles", dto.STRING, dto.CONTAINS_ANY, "viewer", "editor"), attributes, "in"},
+		{"contains none", segment().Rule("roles", dto.STRING, dto.CONTAINS_ANY, "viewer", "owner"), attributes, "out"},
+		{"contains all", segment().Rule("roles", dto.STRING, dto.CONTAINS_ALL, "editor", "admin"), attributes, "in"},
+		{"contains not all", segment().Rule("roles", dto.STRING, dto.CONTAINS_ALL, "editor", "viewer"), attributes, "out"},
+		{"contains all ints", segment().Rule("ids", dto.INT64, dto.CONTAINS_ALL, 3000000000, 7), attributes, "in"},
+		{"contains any version", segment().Rule("versions", dto.VERSION, dto.CONTAINS_ANY, "2.0.1"), attributes, "in"},
+		{"contains a single value", segment().Rule("role", dto.STRING, dto.CONTAINS_ANY, "viewer", "admin"), attributes, "in"},
+		{"contains all of a single value", segment().Rule("role", dto.STRING, dto.CONTAINS_ALL, "viewer", "admin"), attributes, "out"},
+	})
+}
+
+func TestVersionRangeRules(t *testing.T) {
+	appVersion := func(value string) map[string]interface{} {
+		return map[string]interface{}{"appVersion": value}
+	}
+
+	runRuleTests(t, []ruleTest{
+		{"pessimistic range", segment().Rule("appVersion", dto.VERSION, dto.RANGE, "~> 2.3"), appVersion("2.9.1"), "in"},
+		{"outside pessimistic range", segment().Rule("appVersion", dto.VERSION, dto.RANGE, "~> 2.3"), appVersion("3.0.0"), "out"},
+		{"bounded range", segment().Rule("appVersion", dto.VERSION, dto.RANGE, ">= 1.2, < 2.0"), appVersion("1.9.9"), "in"},
+		{"upper bound excluded", segment().Rule("appVersion", dto.VERSION, dto.RANGE, ">= 1.2, < 2.0"), appVersion("2.0.0"), "out"},
+		{"any of the ranges", segment().Rule("appVersion", dto.VERSION, dto.RANGE, "< 1.0", ">= 3.0"), appVersion("3.1"), "in"},
+		{"invalid attribute version", segment().Rule("appVersion", dto.VERSION, dto.LT, "2.0"), appVersion("latest"), "out"},
+		{"negated invalid attribute version", segment().NotRule("appVersion", dto.VERSION, dto.EQ, "2.0"), appVersion("latest"), "out"},
+		{"version in list", segment().Rule("appVersion", dto.VERSION, dto.IOF, "1.0", "2.0"), appVersion("2.0.0"), "in"},
+	})
+}
+
+func TestPrereleaseVersionRules(t *testing.T) {
+	tests := []struct {
+		prerelease string
+		segment    *fstestdata.SegmentBuilder
+		expected   string
+	}{
+		{impl.PRERELEASE_STRICT, segment().Rule("appVersion", dto.VERSION, dto.RANGE, ">= 2.0"), "out"},
+		{impl.PRERELEASE_STRICT, segment().Rule("appVersion", dto.VERSION, dto.RANGE, ">= 2.0.0-alpha"), "in"},
+		{impl.PRERELEASE_STRICT, segment().Rule("appVersion", dto.VERSION, dto.LT, "2.0"), "in"},
+		{impl.PRERELEASE_CORE, segment().Rule("appVersion", dto.VERSION, dto.RANGE, ">= 2.0"), "in"},
+		{impl.PRERELEASE_CORE, segment().Rule("appVersion", dto.VERSION, dto.EQ, "2.0"), "in"},
+		{impl.PRERELEASE_EXCLUDE, segment().Rule("appVersion", dto.VERSION, dto.RANGE, ">= 2.0.0-alpha"), "out"},
+		{impl.PRERELEASE_EXCLUDE, segment().NotRule("appVersion", dto.VERSION, dto.EQ, "2.0"), "out"},
+	}
+	for _, test := range tests {
+		options := impl.Options{Prerelease: test.prerelease}
+		detail := evaluateSegmentWithOptions(t, options, test.segment, map[string]interface{}{"appVersion": "2.0.0-beta"})
+		if detail.Value != test.expected {
+			t.Fatalf("%s: expected %s, got %v %+v", test.prerelease, test.expected, detail.Value, detail.Reason)
+		}
+	}
+}
+
+func TestInvalidVersionNeverMatches(t *testing.T) {
+	appVersion := map[string]interface{}{"appVersion": "2.0.0"}
+	runRuleTests(t, []ruleTest{
+		{"invalid version", segment().Rule("appVersion", dto.VERSION, dto.GT, "two"), appVersion, "out"},
+		{"invalid range", segment().Rule("appVersion", dto.VERSION, dto.RANGE, "~> two"), appVersion, "out"},
+		{"no version", segment().Rule("appVersion", dto.VERSION, dto.EQ), appVersion, "out"},
+		{"negated invalid version", segment().NotRule("appVersion", dto.VERSION, dto.EQ, "two"), appVersion, "out"},
+		{"other rules still evaluated", segment().Rule("appVersion", dto.VERSION, dto.GT, "two").
+			OrRule("appVersion", dto.VERSION, dto.GT, "1.0"), appVersion, "in"},
+	})
+}
+
+func TestBucketBy(t *testing.T) {
+	td := fstestdata.New()
+	td.Update(td.Flag("by-org").StringFlag().Variations("a", "b").FallthroughTraffic(map[string]int{"a": 50000, "b": 50000}).BucketBy("orgId"))
+	service := newTestDataService(t, td, impl.Options{})
+
+	tests := []struct {
+		name     string
+		orgId    interface{}
+		bucketBy string
+	}{
+		{"string attribute", "acme", "orgId"},
+		{"number attribute", 1234, "orgId"},
+		{"missing attribute", nil, ""},
+		{"unsupported attribute", []string{"acme"}, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			variants := map[interface{}]bool{}
+			for i := 0; i < 50; i++ {
+				user := model.FSUser{UserId: fmt.Sprintf("user-%d", i), Attributes: map[string]interface{}{}}
+				if test.orgId != nil {
+					user.Attributes["orgId"] = test.orgId
+				}
+				detail := service.StringVariationDetail(model.FSFlag{FlagId: "by-org", DefaultValue: "default"}, user)
+				if detail.Reason.Kind != model.REASON_FALLTHROUGH || detail.Reason.BucketBy != test.bucketBy {
+					t.Fatalf("unexpected reason %+v", detail.Reason)
+				}
+				variants[detail.Value] = true
+			}
+			if test.bucketBy != "" && len(variants) != 1 {
+				t.Fatalf("expected every user of the org to get the same variant, got %v", variants)
+			}
+			// users without the attribute are bucketed by userId
+			if test.bucketBy == "" && len(variants) != 2 {
+				t.Fatalf("expected users to be split between variants, got %v", variants)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package sidecar
+
+import (
+	"encoding/json"
+	"github.com/flagsense/go-sdk/pkg/dto"
+	"github.com/flagsense/go-sdk/pkg/model"
+	"github.com/flagsense/go-sdk/pkg/services/impl"
+	"net/http"
+	"strings"
+)
+
+const (
+	EVALUATE_PATH     = "/evaluate/"
+	EVALUATE_ALL_PATH = "/evaluate-all"
+	STATUS_PATH       = "/status"
+
+	CLIENT_SIDE_ONLY_PARAM = "clientSideOnly"
+)
+
+// Sidecar evaluates flags over http for services which can not embed the go sdk, so every language
+// gets the same bucketing. The request body is the user, e.g. {"userId":"u1","attributes":{"plan":"pro"}}.
+type Sidecar struct {
+	Service *impl.FlagsenseServiceImpl
+}
+
+func NewSidecar(service *impl.FlagsenseServiceImpl) *Sidecar {
+	return &Sidecar{
+		Service: service,
+	}
+}
+
+func (s *Sidecar) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(EVALUATE_PATH, s.evaluate)
+	mux.HandleFunc(EVALUATE_ALL_PATH, s.evaluateAll)
+	mux.HandleFunc(STATUS_PATH, s.status)
+	return mux
+}
+
+// evaluate uses the type of the flag itself, errors are returned with a null value so callers apply their own default
+func (s *Sidecar) evaluate(w http.ResponseWriter, req *http.Request) {
+	flagId := strings.TrimPrefix(req.URL.Path, EVALUATE_PATH)
+	if req.Method != http.MethodPost || flagId == "" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	user, ok := readUser(w, req)
+	if !ok {
+		return
+	}
+	writeJson(w, http.StatusOK, s.evaluateFlag(req, flagId, user))
+}
+
+func (s *Sidecar) evaluateAll(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	user, ok := readUser(w, req)
+	if !ok {
+		return
+	}
+	writeJson(w, http.StatusOK, s.Service.AllFlagsState(user, model.FSAllFlagsStateOptions{
+		ClientSideOnly: req.URL.Query().Get(CLIENT_SIDE_ONLY_PARAM) == "true",
+		WithReasons:    true,
+	}))
+}
+
+func (s *Sidecar) status(w http.ResponseWriter, req *http.Request) {
+	if !s.Service.InitializationComplete() {
+		writeJson(w, http.StatusServiceUnavailable, s.Service.DataSourceStatus())
+		return
+	}
+	writeJson(w, http.StatusOK, s.Service.DataSourceStatus())
+}
+
+func (s *Sidecar) evaluateFlag(req *http.Request, flagId string, user model.FSUser) model.FSEvaluationDetail {
+	if !s.Service.InitializationComplete() {
+		return model.FSEvaluationDetail{Reason: model.NewErrorReason(model.ERROR_CLIENT_NOT_READY)}
+	}
+	flagDTO, ok := s.Service.Store.GetFlag(flagId)
+	if !ok {
+		return model.FSEvaluationDetail{Reason: model.NewErrorReason(model.ERROR_FLAG_NOT_FOUND)}
+	}
+
+	ctx := req.Context()
+	var detail model.FSEvaluationDetail
+	switch flagDTO.Type {
+	case dto.BOOL:
+		detail = s.Service.BooleanVariationDetailCtx(ctx, model.FSFlag{FlagId: flagId, DefaultValue: false}, user)
+	case dto.INT:
+		detail = s.Service.Int64VariationDetailCtx(ctx, model.FSFlag{FlagId: flagId, DefaultValue: int64(0)}, user)
+	case dto.DOUBLE:
+		detail = s.Service.DecimalVariationDetailCtx(ctx, model.FSFlag{FlagId: flagId, DefaultValue: float64(0)}, user)
+	case dto.STRING:
+		detail = s.Service.StringVariationDetailCtx(ctx, model.FSFlag{FlagId: flagId, DefaultValue: ""}, user)
+	case dto.JSON:
+		var value interface{}
+		detail = s.Service.JSONVariationCtx(ctx, model.FSFlag{FlagId: flagId}, user, &value)
+	default:
+		return model.FSEvaluationDetail{Reason: model.NewErrorReason(model.ERROR_WRONG_TYPE)}
+	}
+	if detail.Reason.IsError() {
+		detail.Key = ""
+		detail.Value = nil
+	}
+	return detail
+}
+
+func readUser(w http.ResponseWriter, req *http.Request) (model.FSUser, bool) {
+	var user model.FSUser
+	// numbers stay json.Number, so int64 attributes are compared without rounding
+	decoder := json.NewDecoder(req.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&user); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return user, false
+	}
+	return user, true
+}
+
+// writeJson encodes the value before writing anything, so a failed encoding can still answer with an error status
+func writeJson(w http.ResponseWriter, status int, value interface{}) {
+	content, err := json.Marshal(value)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set(impl.CONTENT_TYPE, impl.APPLICATION_JSON)
+	w.WriteHeader(status)
+	w.Write(content)
+}
@@ -0,0 +1,136 @@
+package sidecar_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/flagsense/go-sdk/constants"
+	"github.com/flagsense/go-sdk/pkg/enums"
+	"github.com/flagsense/go-sdk/pkg/fstestdata"
+	"github.com/flagsense/go-sdk/pkg/model"
+	"github.com/flagsense/go-sdk/pkg/services/impl"
+	"github.com/flagsense/go-sdk/pkg/sidecar"
+)
+
+func newSidecar(t *testing.T) *httptest.Server {
+	td := fstestdata.New()
+	td.Update(td.Flag("bool-flag").BooleanFlag().VariationForUser("beta-user", true).FallthroughVariation(false))
+	td.Update(td.Flag("string-flag").StringFlag().Variations("a", "b").FallthroughVariation("b").ClientSide(true))
+	td.Update(td.Flag("int-flag").IntegerFlag().Variations(1, 2).FallthroughVariation(2))
+
+	captureEvents := false
+	service := impl.NewFlagsenseServiceWithOptions("sidecar", "secret", enums.NewEnvironment(constants.DEV), impl.Options{
+		CaptureEvents:     &captureEvents,
+		DataSourceFactory: td,
+	})
+	server := httptest.NewServer(sidecar.NewSidecar(service).Handler())
+	t.Cleanup(func() {
+		server.Close()
+		service.Close()
+	})
+	return server
+}
+
+func TestSidecarEvaluate(t *testing.T) {
+	server := newSidecar(t)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		value  interface{}
+		reason model.FSEvaluationReason
+	}{
+		{"target match", http.MethodPost, "/evaluate/bool-flag", `{"userId":"beta-user"}`, http.StatusOK,
+			true, model.NewReason(model.REASON_TARGET_MATCH)},
+		{"fallthrough", http.MethodPost, "/evaluate/bool-flag", `{"userId":"user"}`, http.StatusOK,
+			false, model.NewReason(model.REASON_FALLTHROUGH)},
+		{"string flag", http.MethodPost, "/evaluate/string-flag", `{"userId":"user"}`, http.StatusOK,
+			"b", model.NewReason(model.REASON_FALLTHROUGH)},
+		{"int flag", http.MethodPost, "/evaluate/int-flag", `{"userId":"user"}`, http.StatusOK,
+			float64(2), model.NewReason(model.REASON_FALLTHROUGH)},
+		{"unknown flag", http.MethodPost, "/evaluate/other", `{"userId":"user"}`, http.StatusOK,
+			nil, model.NewErrorReason(model.ERROR_FLAG_NOT_FOUND)},
+		{"missing user", http.MethodPost, "/evaluate/bool-flag", `{}`, http.StatusOK,
+			nil, model.NewErrorReason(model.ERROR_USER_NOT_SPECIFIED)},
+		{"broken user", http.MethodPost, "/evaluate/bool-flag", `{userId`, http.StatusBadRequest, nil, model.FSEvaluationReason{}},
+		{"get", http.MethodGet, "/evaluate/bool-flag", ``, http.StatusNotFound, nil, model.FSEvaluationReason{}},
+		{"no flag id", http.MethodPost, "/evaluate/", `{"userId":"user"}`, http.StatusNotFound, nil, model.FSEvaluationReason{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req, err := http.NewRequest(test.method, server.URL+test.path, bytes.NewBufferString(test.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			response, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer response.Body.Close()
+			if response.StatusCode != test.status {
+				t.Fatalf("expected status %d, got %d", test.status, response.StatusCode)
+			}
+			if test.status != http.StatusOK {
+				return
+			}
+			var detail model.FSEvaluationDetail
+			if err := json.NewDecoder(response.Body).Decode(&detail); err != nil {
+				t.Fatal(err)
+			}
+			if detail.Value != test.value || detail.Reason != test.reason {
+				t.Fatalf("expected %v %+v, got %v %+v", test.value, test.reason, detail.Value, detail.Reason)
+			}
+		})
+	}
+}
+
+func TestSidecarEvaluateAll(t *testing.T) {
+	server := newSidecar(t)
+
+	for query, expected := range map[string][]string{
+		"":                     {"bool-flag", "string-flag", "int-flag"},
+		"?clientSideOnly=true": {"string-flag"},
+	} {
+		response, err := http.Post(server.URL+"/evaluate-all"+query, "application/json", bytes.NewBufferString(`{"userId":"user"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var state model.FSAllFlagsState
+		err = json.NewDecoder(response.Body).Decode(&state)
+		response.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !state.Valid || len(state.Flags) != len(expected) {
+			t.Fatalf("%q: unexpected state %+v", query, state)
+		}
+		for _, flagId := range expected {
+			if _, ok := state.GetFlagState(flagId); !ok {
+				t.Fatalf("%q: missing %s in %+v", query, flagId, state)
+			}
+		}
+	}
+}
+
+func TestSidecarStatus(t *testing.T) {
+	server := newSidecar(t)
+
+	response, err := http.Get(server.URL + "/status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	var status model.FSDataSourceStatus
+	if err := json.NewDecoder(response.Body).Decode(&status); err != nil {
+		t.Fatal(err)
+	}
+	if response.StatusCode != http.StatusOK || status.State != model.DATA_SOURCE_VALID {
+		t.Fatalf("unexpected status %d %+v", response.StatusCode, status)
+	}
+}
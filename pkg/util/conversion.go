@@ -1,5 +1,15 @@
 package util
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+var ErrIntegerOverflow = errors.New("value overflows the integer type")
+
 func ConvertToFloat64(source interface{}) float64 {
 	switch source.(type) {
 	case float64:
@@ -12,22 +22,140 @@ func ConvertToFloat64(source interface{}) float64 {
 		return float64(source.(int64))
 	case int32:
 		return float64(source.(int32))
+	case json.Number:
+		value, _ := source.(json.Number).Float64()
+		return value
 	default:
 		return source.(float64)
 	}
 }
 
-func ConvertToInt32(source interface{}) int32 {
-	switch source.(type) {
+// ToInt64 converts any number, floats are truncated towards zero. Values out of the int64 range are an error.
+func ToInt64(source interface{}) (int64, error) {
+	switch value := source.(type) {
 	case int:
-		return int32(source.(int))
+		return int64(value), nil
+	case int8:
+		return int64(value), nil
+	case int16:
+		return int64(value), nil
+	case int32:
+		return int64(value), nil
 	case int64:
-		return int32(source.(int64))
+		return value, nil
+	case uint:
+		return uint64ToInt64(uint64(value))
+	case uint8:
+		return int64(value), nil
+	case uint16:
+		return int64(value), nil
+	case uint32:
+		return int64(value), nil
+	case uint64:
+		return uint64ToInt64(value)
 	case float32:
-		return int32(source.(float32))
+		return float64ToInt64(float64(value))
 	case float64:
-		return int32(source.(float64))
+		return float64ToInt64(value)
+	case json.Number:
+		if converted, err := value.Int64(); err == nil {
+			return converted, nil
+		}
+		converted, err := value.Float64()
+		if err != nil {
+			return 0, err
+		}
+		return float64ToInt64(converted)
+	default:
+		return 0, fmt.Errorf("value is not a number, %+v", source)
+	}
+}
+
+// ToInt32 is ToInt64 limited to the int32 range
+func ToInt32(source interface{}) (int32, error) {
+	value, err := ToInt64(source)
+	if err != nil {
+		return 0, err
+	}
+	if value < math.MinInt32 || value > math.MaxInt32 {
+		return 0, ErrIntegerOverflow
+	}
+	return int32(value), nil
+}
+
+func IsNumber(source interface{}) bool {
+	switch source.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, json.Number:
+		return true
 	default:
-		return source.(int32)
+		return false
+	}
+}
+
+func uint64ToInt64(value uint64) (int64, error) {
+	if value > math.MaxInt64 {
+		return 0, ErrIntegerOverflow
+	}
+	return int64(value), nil
+}
+
+func float64ToInt64(value float64) (int64, error) {
+	// 2^63 itself is out of range, it is the first float64 above math.MaxInt64
+	if math.IsNaN(value) || value < math.MinInt64 || value >= math.MaxInt64 {
+		return 0, ErrIntegerOverflow
+	}
+	return int64(value), nil
+}
+
+// unix timestamps from this value on are read as milliseconds, as seconds they would be after the year 5000
+const UNIX_MILLIS_THRESHOLD = 1e11
+
+// ToTime converts a time.Time, an RFC3339 string or a unix timestamp in seconds or milliseconds
+func ToTime(source interface{}) (time.Time, error) {
+	switch value := source.(type) {
+	case time.Time:
+		return value, nil
+	case *time.Time:
+		if value == nil {
+			return time.Time{}, errors.New("time is nil")
+		}
+		return *value, nil
+	case string:
+		return time.Parse(time.RFC3339, value)
+	case float32, float64:
+		return unixFloatToTime(ConvertToFloat64(value))
+	case json.Number:
+		if converted, err := value.Int64(); err == nil {
+			return unixToTime(converted), nil
+		}
+		converted, err := value.Float64()
+		if err != nil {
+			return time.Time{}, err
+		}
+		return unixFloatToTime(converted)
+	default:
+		converted, err := ToInt64(source)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("value is not a time, %+v", source)
+		}
+		return unixToTime(converted), nil
+	}
+}
+
+func unixToTime(value int64) time.Time {
+	if value >= UNIX_MILLIS_THRESHOLD || value <= -UNIX_MILLIS_THRESHOLD {
+		return time.UnixMilli(value)
+	}
+	return time.Unix(value, 0)
+}
+
+func unixFloatToTime(value float64) (time.Time, error) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return time.Time{}, fmt.Errorf("value is not a time, %v", value)
+	}
+	if math.Abs(value) >= UNIX_MILLIS_THRESHOLD {
+		value = value / 1000
 	}
+	seconds, fraction := math.Modf(value)
+	return time.Unix(int64(seconds), int64(fraction*float64(time.Second))), nil
 }
@@ -2,13 +2,14 @@ package util
 
 import (
 	"fmt"
-	"github.com/hashicorp/go-version"
+	"strings"
 )
 
+// Contains skips elements of another type than search, so they never decide the result of a "not in" check
 func Contains(source []interface{}, search interface{}) bool {
 	for _, find := range source {
 		if fmt.Sprintf("%T", find) != fmt.Sprintf("%T", search) {
-			return false
+			continue
 		}
 
 		switch search.(type) {
@@ -41,22 +42,19 @@ func Contains(source []interface{}, search interface{}) bool {
 	return false
 }
 
-func ContainsString(source []string, search string) bool {
+// ContainsFold is Contains for strings under unicode case folding
+func ContainsFold(source []interface{}, search string) bool {
 	for _, find := range source {
-		if find == search {
+		if value, ok := find.(string); ok && strings.EqualFold(value, search) {
 			return true
 		}
 	}
 	return false
 }
 
-func ContainsVersion(source []interface{}, search *version.Version) bool {
-	for _, versionString := range source {
-		valueVersion, err := version.NewVersion(versionString.(string))
-		if err != nil {
-			return false
-		}
-		if valueVersion.Equal(search) {
+func ContainsString(source []string, search string) bool {
+	for _, find := range source {
+		if find == search {
 			return true
 		}
 	}
@@ -0,0 +1,30 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/flagsense/go-sdk/pkg/util"
+)
+
+func TestContains(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   []interface{}
+		search   interface{}
+		expected bool
+	}{
+		{"same type", []interface{}{int32(4), int32(5)}, int32(5), true},
+		{"missing", []interface{}{int32(4), int32(5)}, int32(6), false},
+		{"other type before the match", []interface{}{"a", int32(5)}, int32(5), true},
+		{"only other types", []interface{}{"5", int64(5), 5.0}, int32(5), false},
+		{"string", []interface{}{int32(5), "a"}, "a", true},
+		{"empty", []interface{}{}, "a", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := util.Contains(test.source, test.search); actual != test.expected {
+				t.Fatalf("expected %v, got %v", test.expected, actual)
+			}
+		})
+	}
+}
@@ -1,5 +1,11 @@
 package util
 
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
 func ContainsKey(mapObject map[string]interface{}, key string) bool {
 	if mapObject == nil {
 		return false
@@ -18,3 +24,116 @@ func SafeGetValue(mapObject map[string]interface{}, key string) (interface{}, bo
 	}
 	return nil, false
 }
+
+// SplitPath splits an attribute key into the keys of its nested values, a key starting with "/" is a json pointer,
+// otherwise dots separate the keys. It returns nil for a flat key.
+func SplitPath(key string) []string {
+	if strings.HasPrefix(key, "/") {
+		parts := strings.Split(key[1:], "/")
+		for i, part := range parts {
+			parts[i] = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		}
+		return parts
+	}
+	if strings.Contains(key, ".") {
+		return strings.Split(key, ".")
+	}
+	return nil
+}
+
+// GetPath looks up a nested value through maps, slices and arrays by index, and structs by json name or field name.
+// Like SafeGetValue, a missing or nil value is not found.
+func GetPath(mapObject map[string]interface{}, path []string) (interface{}, bool) {
+	if len(path) == 0 {
+		return nil, false
+	}
+	value, ok := SafeGetValue(mapObject, path[0])
+	if !ok {
+		return nil, false
+	}
+	for _, key := range path[1:] {
+		value, ok = getChild(value, key)
+		if !ok {
+			return nil, false
+		}
+	}
+	// pointers reached through structs are followed, so *string fields match like strings
+	reflected := reflect.ValueOf(value)
+	for reflected.Kind() == reflect.Ptr {
+		if reflected.IsNil() {
+			return nil, false
+		}
+		reflected = reflected.Elem()
+	}
+	if !reflected.IsValid() {
+		return nil, false
+	}
+	return reflected.Interface(), true
+}
+
+func getChild(parent interface{}, key string) (interface{}, bool) {
+	if mapObject, ok := parent.(map[string]interface{}); ok {
+		return SafeGetValue(mapObject, key)
+	}
+	if list, ok := parent.([]interface{}); ok {
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 || index >= len(list) {
+			return nil, false
+		}
+		return list[index], true
+	}
+
+	value := reflect.ValueOf(parent)
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil, false
+		}
+		value = value.Elem()
+	}
+	switch value.Kind() {
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		child := value.MapIndex(reflect.ValueOf(key).Convert(value.Type().Key()))
+		if !child.IsValid() {
+			return nil, false
+		}
+		return child.Interface(), true
+	case reflect.Slice, reflect.Array:
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 || index >= value.Len() {
+			return nil, false
+		}
+		return value.Index(index).Interface(), true
+	case reflect.Struct:
+		return getField(value, key)
+	default:
+		return nil, false
+	}
+}
+
+func getField(value reflect.Value, key string) (interface{}, bool) {
+	valueType := value.Type()
+	var byName reflect.Value
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		tagName := strings.Split(field.Tag.Get("json"), ",")[0]
+		if tagName == "-" {
+			continue
+		}
+		if tagName == key {
+			return value.Field(i).Interface(), true
+		}
+		if tagName == "" && field.Name == key {
+			byName = value.Field(i)
+		}
+	}
+	if byName.IsValid() {
+		return byName.Interface(), true
+	}
+	return nil, false
+}